@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/irisnet/irishub/app/v2/htlc/internal/types"
+	"github.com/irisnet/irishub/client/context"
+	"github.com/irisnet/irishub/codec"
+	sdk "github.com/irisnet/irishub/types"
+)
+
+const (
+	flagSender          = "sender"
+	flagReceiver        = "receiver"
+	flagState           = "state"
+	flagMaxExpireHeight = "max-expire-height"
+	flagPage            = "page"
+	flagLimit           = "limit"
+)
+
+// GetQueryCmd returns the CLI query commands for the htlc module
+func GetQueryCmd(cdc *codec.Codec) *cobra.Command {
+	htlcQueryCmd := &cobra.Command{
+		Use:   "htlc",
+		Short: "Querying commands for the htlc module",
+	}
+
+	htlcQueryCmd.AddCommand(
+		GetCmdQueryHTLC(cdc),
+		GetCmdQueryHTLCs(cdc),
+		GetCmdQueryExpiringHTLCs(cdc),
+		GetCmdQueryParams(cdc),
+	)
+
+	return htlcQueryCmd
+}
+
+// GetCmdQueryHTLC implements `iriscli query htlc htlc <secret-hash-lock>`
+func GetCmdQueryHTLC(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "htlc [secret-hash-lock]",
+		Short: "Query a HTLC by its secret hash lock",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			secretHashLock, err := hex.DecodeString(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid secret hash lock: %s", args[0])
+			}
+
+			params := cdc.MustMarshalJSON(types.NewQueryHTLCParams(secretHashLock))
+			res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryHTLC), params)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+// GetCmdQueryHTLCs implements `iriscli query htlc list --sender ... --receiver ... --state ...`
+func GetCmdQueryHTLCs(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Query HTLCs filtered by sender, receiver or state",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			queryParams := types.NewQueryHTLCsParams(viper.GetInt(flagPage), viper.GetInt(flagLimit))
+
+			endpoint := types.QueryHTLCsByState
+			switch {
+			case viper.GetString(flagSender) != "":
+				sender, err := sdk.AccAddressFromBech32(viper.GetString(flagSender))
+				if err != nil {
+					return err
+				}
+				queryParams.Sender = sender
+				endpoint = types.QueryHTLCsBySender
+			case viper.GetString(flagReceiver) != "":
+				receiver, err := sdk.AccAddressFromBech32(viper.GetString(flagReceiver))
+				if err != nil {
+					return err
+				}
+				queryParams.Receiver = receiver
+				endpoint = types.QueryHTLCsByReceiver
+			case viper.GetString(flagState) != "":
+				state, err := parseHTLCState(viper.GetString(flagState))
+				if err != nil {
+					return err
+				}
+				queryParams.State = state
+			default:
+				return fmt.Errorf("one of --%s, --%s or --%s must be specified", flagSender, flagReceiver, flagState)
+			}
+
+			params := cdc.MustMarshalJSON(queryParams)
+			res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, endpoint), params)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagSender, "", "filter by sender address")
+	cmd.Flags().String(flagReceiver, "", "filter by receiver address")
+	cmd.Flags().String(flagState, "", "filter by state (open|completed|expired|refunded)")
+	cmd.Flags().Int(flagPage, 1, "pagination page")
+	cmd.Flags().Int(flagLimit, 100, "pagination limit")
+
+	return cmd
+}
+
+// GetCmdQueryExpiringHTLCs implements `iriscli query htlc expiring --max-expire-height ...`
+func GetCmdQueryExpiringHTLCs(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "expiring",
+		Short: "Query HTLCs that expire at or below a given block height",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			queryParams := types.NewQueryExpiringHTLCsParams(
+				viper.GetUint64(flagMaxExpireHeight), viper.GetInt(flagPage), viper.GetInt(flagLimit),
+			)
+
+			params := cdc.MustMarshalJSON(queryParams)
+			res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryExpiringHTLCs), params)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint64(flagMaxExpireHeight, 0, "only return HTLCs expiring at or below this block height")
+	cmd.Flags().Int(flagPage, 1, "pagination page")
+	cmd.Flags().Int(flagLimit, 100, "pagination limit")
+
+	return cmd
+}
+
+// GetCmdQueryParams implements `iriscli query htlc params`
+func GetCmdQueryParams(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "params",
+		Short: "Query the current htlc module parameters",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryParams), nil)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+func parseHTLCState(s string) (types.HTLCState, error) {
+	switch s {
+	case "open":
+		return types.StateOpen, nil
+	case "completed":
+		return types.StateCompleted, nil
+	case "expired":
+		return types.StateExpired, nil
+	case "refunded":
+		return types.StateRefunded, nil
+	default:
+		return 0, fmt.Errorf("unknown htlc state: %s", s)
+	}
+}
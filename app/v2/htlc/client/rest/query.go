@@ -0,0 +1,150 @@
+package rest
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/irisnet/irishub/app/v2/htlc/internal/types"
+	"github.com/irisnet/irishub/client/context"
+	"github.com/irisnet/irishub/client/utils"
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// RegisterRoutes registers the htlc module's REST query routes. This SDK vintage predates
+// protobuf Query services and gRPC-gateway, so - like the rest of this module's query path -
+// these are hand-written gorilla/mux handlers over the legacy Amino sdk.Querier in
+// ../../internal/keeper/querier.go, not a generated gateway.
+func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router) {
+	r.HandleFunc("/htlc/htlcs/expiring", queryExpiringHTLCsHandlerFn(cliCtx)).Methods("GET")
+	r.HandleFunc("/htlc/htlcs/{secret-hash-lock}", queryHTLCHandlerFn(cliCtx)).Methods("GET")
+	r.HandleFunc("/htlc/htlcs", queryHTLCsHandlerFn(cliCtx)).Methods("GET")
+	r.HandleFunc("/htlc/parameters", queryParamsHandlerFn(cliCtx)).Methods("GET")
+}
+
+func queryHTLCHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		secretHashLock, err := hex.DecodeString(vars["secret-hash-lock"])
+		if err != nil {
+			utils.WriteErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid secret hash lock: %s", vars["secret-hash-lock"]))
+			return
+		}
+
+		params := cliCtx.Codec.MustMarshalJSON(types.NewQueryHTLCParams(secretHashLock))
+		res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryHTLC), params)
+		if err != nil {
+			utils.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		utils.PostProcessResponse(w, cliCtx.Codec, res, cliCtx.Indent)
+	}
+}
+
+// queryHTLCsHandlerFn serves sender-, receiver- and state-filtered HTLC lists, matching the
+// filters `iriscli query htlc list` exposes, behind a single paginated REST endpoint
+func queryHTLCsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		page, limit := utils.ParseHTTPArgsWithLimit(r, 100)
+		queryParams := types.NewQueryHTLCsParams(page, limit)
+
+		var endpoint string
+		switch {
+		case query.Get("sender") != "":
+			sender, err := sdk.AccAddressFromBech32(query.Get("sender"))
+			if err != nil {
+				utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			queryParams.Sender = sender
+			endpoint = types.QueryHTLCsBySender
+		case query.Get("receiver") != "":
+			receiver, err := sdk.AccAddressFromBech32(query.Get("receiver"))
+			if err != nil {
+				utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			queryParams.Receiver = receiver
+			endpoint = types.QueryHTLCsByReceiver
+		case query.Get("state") != "":
+			state, err := parseHTLCState(query.Get("state"))
+			if err != nil {
+				utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			queryParams.State = state
+			endpoint = types.QueryHTLCsByState
+		default:
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "one of sender, receiver or state must be specified")
+			return
+		}
+
+		params := cliCtx.Codec.MustMarshalJSON(queryParams)
+		res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, endpoint), params)
+		if err != nil {
+			utils.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		utils.PostProcessResponse(w, cliCtx.Codec, res, cliCtx.Indent)
+	}
+}
+
+func parseHTLCState(s string) (types.HTLCState, error) {
+	switch s {
+	case "open":
+		return types.StateOpen, nil
+	case "completed":
+		return types.StateCompleted, nil
+	case "expired":
+		return types.StateExpired, nil
+	case "refunded":
+		return types.StateRefunded, nil
+	default:
+		return 0, fmt.Errorf("unknown htlc state: %s", s)
+	}
+}
+
+// queryExpiringHTLCsHandlerFn serves HTLCs that expire at or below a given block height
+func queryExpiringHTLCsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		maxExpireHeight, err := strconv.ParseUint(query.Get("max_expire_height"), 10, 64)
+		if err != nil {
+			utils.WriteErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid max_expire_height: %s", query.Get("max_expire_height")))
+			return
+		}
+
+		page, limit := utils.ParseHTTPArgsWithLimit(r, 100)
+		queryParams := types.NewQueryExpiringHTLCsParams(maxExpireHeight, page, limit)
+
+		params := cliCtx.Codec.MustMarshalJSON(queryParams)
+		res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryExpiringHTLCs), params)
+		if err != nil {
+			utils.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		utils.PostProcessResponse(w, cliCtx.Codec, res, cliCtx.Indent)
+	}
+}
+
+func queryParamsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryParams), nil)
+		if err != nil {
+			utils.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		utils.PostProcessResponse(w, cliCtx.Codec, res, cliCtx.Indent)
+	}
+}
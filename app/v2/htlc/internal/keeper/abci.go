@@ -0,0 +1,159 @@
+package keeper
+
+import (
+	"fmt"
+
+	"github.com/irisnet/irishub/app/v2/htlc/internal/types"
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// EndBlocker expires due HTLCs and routes, and auto-claims any route hop whose secret has
+// already been revealed downstream
+func (k Keeper) EndBlocker(ctx sdk.Context) sdk.Tags {
+	tags := sdk.EmptyTags()
+	tags = tags.AppendTags(k.expireHTLCs(ctx))
+	tags = tags.AppendTags(k.expireRouteHops(ctx))
+	tags = tags.AppendTags(k.claimRevealedRouteHops(ctx))
+	return tags
+}
+
+// expireHTLCs walks the expiration queue and flips every due, still-open HTLC to StateExpired
+func (k Keeper) expireHTLCs(ctx sdk.Context) sdk.Tags {
+	store := ctx.KVStore(k.storeKey)
+	tags := sdk.EmptyTags()
+
+	iterator := sdk.KVStorePrefixIterator(store, PrefixHTLCExpireQueue)
+	defer iterator.Close()
+
+	var due [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		expireHeight, secretHashLock := splitHTLCExpireQueueKey(iterator.Key())
+		if expireHeight > uint64(ctx.BlockHeight()) {
+			break
+		}
+		due = append(due, secretHashLock)
+	}
+
+	for _, secretHashLock := range due {
+		htlc, err := k.GetHTLC(ctx, secretHashLock)
+		if err != nil {
+			continue
+		}
+
+		k.DeleteHTLCFromExpireQueue(ctx, htlc.ExpireHeight, secretHashLock)
+
+		if htlc.State != types.StateOpen {
+			continue
+		}
+
+		htlc.State = types.StateExpired
+		k.SetHTLC(ctx, htlc, secretHashLock)
+
+		tags = tags.AppendTags(sdk.NewTags(types.TagSecretHashLock, secretHashLock))
+	}
+
+	return tags
+}
+
+// expireRouteHops walks the route hop expiration queue and flips every due, still-open hop to
+// StateExpired
+func (k Keeper) expireRouteHops(ctx sdk.Context) sdk.Tags {
+	store := ctx.KVStore(k.storeKey)
+	tags := sdk.EmptyTags()
+
+	iterator := sdk.KVStorePrefixIterator(store, PrefixHTLCRouteExpireQueue)
+	defer iterator.Close()
+
+	type dueHop struct {
+		expireHeight   uint64
+		secretHashLock []byte
+		hopIndex       int
+	}
+	var due []dueHop
+	for ; iterator.Valid(); iterator.Next() {
+		expireHeight, secretHashLock, hopIndex := splitHTLCRouteExpireQueueKey(iterator.Key())
+		if expireHeight > uint64(ctx.BlockHeight()) {
+			break
+		}
+		due = append(due, dueHop{expireHeight, secretHashLock, hopIndex})
+	}
+
+	for _, d := range due {
+		k.DeleteRouteHopFromExpireQueue(ctx, d.expireHeight, d.secretHashLock, d.hopIndex)
+
+		route, err := k.GetHTLCByRoute(ctx, d.secretHashLock)
+		if err != nil || d.hopIndex >= len(route.Hops) || route.Hops[d.hopIndex].State != types.StateOpen {
+			continue
+		}
+
+		route.Hops[d.hopIndex].State = types.StateExpired
+		k.SetHTLCRoute(ctx, route)
+
+		tags = tags.AppendTags(sdk.NewTags(types.TagSecretHashLock, d.secretHashLock, types.TagHopIndex, []byte(fmt.Sprintf("%d", d.hopIndex))))
+	}
+
+	return tags
+}
+
+// claimRevealedRouteHops pays out every still-open hop of a route whose secret has already
+// been revealed by the final hop's claim, so upstream relayers never have to resubmit it. It
+// only walks the revealed-route index (see AddRevealedRoute), not every route ever created, so
+// its cost tracks the number of routes currently mid-cascade rather than total route history.
+func (k Keeper) claimRevealedRouteHops(ctx sdk.Context) sdk.Tags {
+	store := ctx.KVStore(k.storeKey)
+	tags := sdk.EmptyTags()
+
+	iterator := sdk.KVStorePrefixIterator(store, PrefixHTLCRouteRevealed)
+	defer iterator.Close()
+
+	var secretHashLocks [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		secretHashLock := make([]byte, len(iterator.Key())-len(PrefixHTLCRouteRevealed))
+		copy(secretHashLock, iterator.Key()[len(PrefixHTLCRouteRevealed):])
+		secretHashLocks = append(secretHashLocks, secretHashLock)
+	}
+
+	for _, secretHashLock := range secretHashLocks {
+		route, err := k.GetHTLCByRoute(ctx, secretHashLock)
+		if err != nil {
+			k.DeleteRevealedRoute(ctx, secretHashLock)
+			continue
+		}
+
+		for i := range route.Hops {
+			if route.Hops[i].State != types.StateOpen {
+				continue
+			}
+			if err := k.payHop(ctx, route.SecretHashLock, i, route.Hops[i]); err != nil {
+				continue
+			}
+			route.Hops[i].State = types.StateCompleted
+			k.DeleteRouteHopFromExpireQueue(ctx, route.Hops[i].ExpireHeight, route.SecretHashLock, i)
+
+			tags = tags.AppendTags(sdk.NewTags(types.TagSecretHashLock, route.SecretHashLock, types.TagHopIndex, []byte(fmt.Sprintf("%d", i))))
+		}
+
+		k.SetHTLCRoute(ctx, route)
+		if routeFullySettled(route) {
+			k.DeleteRevealedRoute(ctx, secretHashLock)
+		}
+	}
+
+	return tags
+}
+
+// splitHTLCExpireQueueKey recovers the expiration height and secret hash lock from a queue key
+func splitHTLCExpireQueueKey(key []byte) (expireHeight uint64, secretHashLock []byte) {
+	rest := key[len(PrefixHTLCExpireQueue):]
+	return sdk.BigEndianToUint64(rest[:8]), rest[8:]
+}
+
+// splitHTLCRouteExpireQueueKey recovers the expiration height, secret hash lock and hop index
+// from a route expiration queue key
+func splitHTLCRouteExpireQueueKey(key []byte) (expireHeight uint64, secretHashLock []byte, hopIndex int) {
+	rest := key[len(PrefixHTLCRouteExpireQueue):]
+	expireHeight = sdk.BigEndianToUint64(rest[:8])
+	secretHashLock = rest[8 : len(rest)-1]
+	hopIndex = int(rest[len(rest)-1])
+	return
+}
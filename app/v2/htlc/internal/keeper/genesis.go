@@ -0,0 +1,23 @@
+package keeper
+
+import (
+	"github.com/irisnet/irishub/app/v2/htlc/internal/types"
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// InitGenesis initializes the htlc module's state from a provided genesis state
+func (k Keeper) InitGenesis(ctx sdk.Context, data types.GenesisState) {
+	k.SetParams(ctx, data.Params)
+
+	for _, genesisHTLC := range data.HTLCs {
+		k.SetHTLC(ctx, genesisHTLC.HTLC, genesisHTLC.SecretHashLock)
+		if genesisHTLC.HTLC.State == types.StateOpen {
+			k.AddHTLCToExpireQueue(ctx, genesisHTLC.HTLC.ExpireHeight, genesisHTLC.SecretHashLock)
+		}
+	}
+}
+
+// ExportGenesis exports the htlc module's state into a genesis state
+func (k Keeper) ExportGenesis(ctx sdk.Context) types.GenesisState {
+	return types.NewGenesisState(k.GetParams(ctx), k.GetAllHTLCs(ctx))
+}
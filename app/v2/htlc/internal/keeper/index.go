@@ -0,0 +1,74 @@
+package keeper
+
+import (
+	"github.com/irisnet/irishub/app/v2/htlc/internal/types"
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// GetHTLCsBySender returns every HTLC sent by the given address, using the sender secondary
+// index rather than scanning the full HTLC store
+func (k Keeper) GetHTLCsBySender(ctx sdk.Context, sender sdk.AccAddress) []types.GenesisHTLC {
+	prefix := append(PrefixHTLCBySender, sender.Bytes()...)
+	return k.getHTLCsByIndex(ctx, prefix, len(prefix))
+}
+
+// GetHTLCsByReceiver returns every HTLC addressed to the given receiver, using the receiver
+// secondary index rather than scanning the full HTLC store
+func (k Keeper) GetHTLCsByReceiver(ctx sdk.Context, receiver sdk.AccAddress) []types.GenesisHTLC {
+	prefix := append(PrefixHTLCByReceiver, receiver.Bytes()...)
+	return k.getHTLCsByIndex(ctx, prefix, len(prefix))
+}
+
+// GetHTLCsByState returns every HTLC currently in the given state, using the state secondary
+// index rather than scanning the full HTLC store
+func (k Keeper) GetHTLCsByState(ctx sdk.Context, state types.HTLCState) []types.GenesisHTLC {
+	prefix := append(PrefixHTLCByState, byte(state))
+	return k.getHTLCsByIndex(ctx, prefix, len(prefix))
+}
+
+// GetExpiringHTLCs returns every open HTLC whose expiration height is less than or equal to
+// maxExpireHeight, by walking the expiration queue rather than the full HTLC store
+func (k Keeper) GetExpiringHTLCs(ctx sdk.Context, maxExpireHeight uint64) []types.GenesisHTLC {
+	store := ctx.KVStore(k.storeKey)
+
+	iterator := sdk.KVStorePrefixIterator(store, PrefixHTLCExpireQueue)
+	defer iterator.Close()
+
+	htlcs := make([]types.GenesisHTLC, 0)
+	for ; iterator.Valid(); iterator.Next() {
+		expireHeight, secretHashLock := splitHTLCExpireQueueKey(iterator.Key())
+		if expireHeight > maxExpireHeight {
+			break
+		}
+
+		htlc, err := k.GetHTLC(ctx, secretHashLock)
+		if err != nil || htlc.State != types.StateOpen {
+			continue
+		}
+		htlcs = append(htlcs, types.GenesisHTLC{SecretHashLock: secretHashLock, HTLC: htlc})
+	}
+
+	return htlcs
+}
+
+// getHTLCsByIndex resolves every secret hash lock stored under the given secondary index
+// prefix into its HTLC
+func (k Keeper) getHTLCsByIndex(ctx sdk.Context, prefix []byte, secretHashLockOffset int) []types.GenesisHTLC {
+	store := ctx.KVStore(k.storeKey)
+
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+
+	htlcs := make([]types.GenesisHTLC, 0)
+	for ; iterator.Valid(); iterator.Next() {
+		secretHashLock := iterator.Key()[secretHashLockOffset:]
+
+		htlc, err := k.GetHTLC(ctx, secretHashLock)
+		if err != nil {
+			continue
+		}
+		htlcs = append(htlcs, types.GenesisHTLC{SecretHashLock: secretHashLock, HTLC: htlc})
+	}
+
+	return htlcs
+}
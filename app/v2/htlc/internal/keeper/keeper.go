@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 	"fmt"
 
+	lru "github.com/hashicorp/golang-lru"
+
 	"github.com/irisnet/irishub/app/v1/params"
 	"github.com/irisnet/irishub/app/v2/htlc/internal/types"
 	"github.com/irisnet/irishub/codec"
@@ -21,6 +23,12 @@ type Keeper struct {
 	codespace sdk.CodespaceType
 	// params subspace
 	paramSpace params.Subspace
+
+	// htlcCache is an optional read-through cache of HTLCs keyed by their secret hash lock,
+	// filled by GetHTLC and invalidated by SetHTLC. It
+	// is nil unless a node opts in via WithCache, in which case GetHTLC - on the hot path during
+	// EndBlocker expiration processing - no longer has to touch the KVStore.
+	htlcCache *lru.Cache
 }
 
 func NewKeeper(cdc *codec.Codec, key sdk.StoreKey, bk types.BankKeeper, codespace sdk.CodespaceType, paramSpace params.Subspace) Keeper {
@@ -33,6 +41,18 @@ func NewKeeper(cdc *codec.Codec, key sdk.StoreKey, bk types.BankKeeper, codespac
 	}
 }
 
+// WithCache returns a copy of the keeper with its read-through HTLC cache enabled, sized to
+// hold up to size entries. It is opt-in: nodes that do not expect a large number of
+// concurrently open HTLCs can skip the extra memory.
+func (k Keeper) WithCache(size int) Keeper {
+	cache, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+	k.htlcCache = cache
+	return k
+}
+
 // Codespace returns the codespace
 func (k Keeper) Codespace() sdk.CodespaceType {
 	return k.codespace
@@ -45,11 +65,40 @@ func (k Keeper) GetCdc() *codec.Codec {
 
 // CreateHTLC creates a HTLC
 func (k Keeper) CreateHTLC(ctx sdk.Context, htlc types.HTLC, secretHashLock []byte) (sdk.Tags, sdk.Error) {
-	// check if the secret hash lock already exists
-	if k.HasSecretHashLock(ctx, secretHashLock) {
+	htlc = normalizeHTLC(htlc)
+
+	// check if the secret hash lock already exists, either as a plain HTLC or as a route
+	if k.HasSecretHashLock(ctx, secretHashLock) || k.HasHTLCRoute(ctx, secretHashLock) {
 		return nil, types.ErrSecretHashLockAlreadyExists(types.DefaultCodespace, fmt.Sprintf("the secret hash lock already exists: %s", hex.EncodeToString(secretHashLock)))
 	}
 
+	// check that the hash function is one the network has agreed to support
+	if !k.isSupportedHashFunc(ctx, htlc.HashFunc) {
+		return nil, types.ErrInvalidHashFunc(k.codespace, fmt.Sprintf("hash function is not supported: %s", htlc.HashFunc))
+	}
+
+	params := k.GetParams(ctx)
+
+	// check that the timelock falls within the bounds params allow, so funds can't be griefed
+	// by a lock so short nobody can react or so long they are dead capital
+	timeLock := htlc.ExpireHeight - uint64(ctx.BlockHeight())
+	if htlc.ExpireHeight <= uint64(ctx.BlockHeight()) || timeLock < params.MinTimeLock || timeLock > params.MaxTimeLock {
+		return nil, types.ErrInvalidTimeLock(k.codespace, fmt.Sprintf("time lock must be between %d and %d blocks", params.MinTimeLock, params.MaxTimeLock))
+	}
+
+	// check that any relayer fee offered meets the network minimum and is less than the
+	// locked amount
+	if !htlc.RelayerFee.IsZero() {
+		if htlc.RelayerFee.Denom != htlc.OutAmount.Denom || htlc.RelayerFee.Amount.GTE(htlc.OutAmount.Amount) {
+			return nil, types.ErrInvalidRelayerFee(k.codespace, "relayer fee must be in the same denom as, and less than, the locked amount")
+		}
+		// MinRelayerFee is a single fixed-denom param, so it can only constrain HTLCs locking
+		// that same denom - comparing raw amounts across denoms would compare unrelated units
+		if params.MinRelayerFee.Denom == htlc.RelayerFee.Denom && htlc.RelayerFee.Amount.LT(params.MinRelayerFee.Amount) {
+			return nil, types.ErrInvalidRelayerFee(k.codespace, fmt.Sprintf("relayer fee is below the network minimum: %s", params.MinRelayerFee))
+		}
+	}
+
 	// transfer the specified tokens to a dedicated HTLC Address
 	htlcAddr := getHTLCAddress(htlc.OutAmount.Denom)
 	if _, err := k.bk.SendCoins(ctx, htlc.Sender, htlcAddr, sdk.Coins{htlc.OutAmount}); err != nil {
@@ -65,6 +114,21 @@ func (k Keeper) CreateHTLC(ctx sdk.Context, htlc types.HTLC, secretHashLock []by
 	// add to the expiration queue
 	k.AddHTLCToExpireQueue(ctx, htlc.ExpireHeight, secretHashLock)
 
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCreateHTLC,
+			sdk.NewAttribute(types.AttributeKeySender, htlc.Sender.String()),
+			sdk.NewAttribute(types.AttributeKeyReceiver, htlc.Receiver.String()),
+			sdk.NewAttribute(types.AttributeKeyReceiverOnOtherChain, hex.EncodeToString(htlc.ReceiverOnOtherChain)),
+			sdk.NewAttribute(types.AttributeKeyHashLock, hex.EncodeToString(secretHashLock)),
+			sdk.NewAttribute(types.AttributeKeyDenom, htlc.OutAmount.Denom),
+			sdk.NewAttribute(types.AttributeKeyAmount, htlc.OutAmount.Amount.String()),
+			sdk.NewAttribute(types.AttributeKeyExpireHeight, fmt.Sprintf("%d", htlc.ExpireHeight)),
+		),
+	)
+
+	// legacy-compat shim: keep emitting the old tags during the deprecation window so existing
+	// relayers built against them keep working until they migrate to the typed events above
 	createTags := sdk.NewTags(
 		types.TagSender, []byte(htlc.Sender.String()),
 		types.TagReceiver, []byte(htlc.Receiver.String()),
@@ -75,7 +139,11 @@ func (k Keeper) CreateHTLC(ctx sdk.Context, htlc types.HTLC, secretHashLock []by
 	return createTags, nil
 }
 
-func (k Keeper) ClaimHTLC(ctx sdk.Context, secret []byte, secretHashLock []byte) (sdk.Tags, sdk.Error) {
+// ClaimHTLC claims the coins locked in an open HTLC on behalf of claimant, the account that
+// submitted the claim transaction. claimant need not be htlc.Receiver: if the HTLC carries a
+// RelayerFee, it is paid to claimant and only the remainder goes to the receiver, so a
+// watchtower/relayer service can claim on behalf of a receiver who is offline.
+func (k Keeper) ClaimHTLC(ctx sdk.Context, claimant sdk.AccAddress, secret []byte, secretHashLock []byte) (sdk.Tags, sdk.Error) {
 
 	// get the htlc
 	htlc, err := k.GetHTLC(ctx, secretHashLock)
@@ -89,24 +157,48 @@ func (k Keeper) ClaimHTLC(ctx sdk.Context, secret []byte, secretHashLock []byte)
 	}
 
 	// check if secret not valid
-	if !bytes.Equal(k.GetSecretHashLock(secret, htlc.Timestamp), secretHashLock) {
+	computedHashLock, hashErr := k.GetSecretHashLock(htlc.HashFunc, secret, htlc.Timestamp)
+	if hashErr != nil {
+		return nil, types.ErrInvalidHashFunc(k.codespace, hashErr.Error())
+	}
+	if !bytes.Equal(computedHashLock, secretHashLock) {
 		return nil, types.ErrInvalidSecret(k.codespace, fmt.Sprintf("invalid secret: %s", hex.EncodeToString(secret)))
 	}
 
-	// do claim
+	// do claim, splitting out the relayer fee (if any) to claimant and the remainder to the
+	// receiver
 	htlcAddr := getHTLCAddress(htlc.OutAmount.Denom)
-	if _, err := k.bk.SendCoins(ctx, htlcAddr, htlc.Receiver, sdk.Coins{htlc.OutAmount}); err != nil {
+	receiverAmount := sdk.NewCoin(htlc.OutAmount.Denom, htlc.OutAmount.Amount.Sub(htlc.RelayerFee.Amount))
+	if _, err := k.bk.SendCoins(ctx, htlcAddr, htlc.Receiver, sdk.Coins{receiverAmount}); err != nil {
 		return nil, err
 	}
+	if !htlc.RelayerFee.IsZero() {
+		if _, err := k.bk.SendCoins(ctx, htlcAddr, claimant, sdk.Coins{htlc.RelayerFee}); err != nil {
+			return nil, err
+		}
+	}
 
-	// update secret and state in HTLC
+	// update secret, relayer and state in HTLC
 	htlc.Secret = secret
+	htlc.Relayer = claimant
 	htlc.State = types.StateCompleted
 	k.SetHTLC(ctx, htlc, secretHashLock)
 
 	// add to coinflow
-	ctx.CoinFlowTags().AppendCoinFlowTag(ctx, htlcAddr.String(), htlc.Receiver.String(), htlc.OutAmount.String(), sdk.CoinHTLCClaimFlow, "")
+	ctx.CoinFlowTags().AppendCoinFlowTag(ctx, htlcAddr.String(), htlc.Receiver.String(), receiverAmount.String(), sdk.CoinHTLCClaimFlow, "")
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeClaimHTLC,
+			sdk.NewAttribute(types.AttributeKeySender, htlc.Sender.String()),
+			sdk.NewAttribute(types.AttributeKeyReceiver, htlc.Receiver.String()),
+			sdk.NewAttribute(types.AttributeKeyHashLock, hex.EncodeToString(secretHashLock)),
+			sdk.NewAttribute(types.AttributeKeySecret, hex.EncodeToString(secret)),
+		),
+	)
 
+	// legacy-compat shim: keep emitting the old tags during the deprecation window so existing
+	// relayers built against them keep working until they migrate to the typed events above
 	calimTags := sdk.NewTags(
 		types.TagSender, []byte(htlc.Sender.String()),
 		types.TagReceiver, []byte(htlc.Receiver.String()),
@@ -143,6 +235,16 @@ func (k Keeper) RefundHTLC(ctx sdk.Context, secretHashLock []byte) (sdk.Tags, sd
 	// add to coinflow
 	ctx.CoinFlowTags().AppendCoinFlowTag(ctx, htlcAddr.String(), htlc.Sender.String(), htlc.OutAmount.String(), sdk.CoinHTLCRefundFlow, "")
 
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeRefundHTLC,
+			sdk.NewAttribute(types.AttributeKeySender, htlc.Sender.String()),
+			sdk.NewAttribute(types.AttributeKeyHashLock, hex.EncodeToString(secretHashLock)),
+		),
+	)
+
+	// legacy-compat shim: keep emitting the old tags during the deprecation window so existing
+	// relayers built against them keep working until they migrate to the typed events above
 	refundTags := sdk.NewTags(
 		types.TagSender, []byte(htlc.Sender.String()),
 		types.TagSecretHashLock, []byte(hex.EncodeToString(secretHashLock)),
@@ -151,9 +253,20 @@ func (k Keeper) RefundHTLC(ctx sdk.Context, secretHashLock []byte) (sdk.Tags, sd
 	return refundTags, nil
 }
 
-// GetSecretHashLock calculates the secret hash lock
-func (k Keeper) GetSecretHashLock(secret []byte, timestamp uint64) []byte {
-	return sdk.SHA256(append(secret, sdk.Uint64ToBigEndian(timestamp)...))
+// GetSecretHashLock calculates the secret hash lock using the specified hash function, so it
+// can be matched against a hash lock generated on a counterparty chain
+func (k Keeper) GetSecretHashLock(hashFunc types.HashFunc, secret []byte, timestamp uint64) ([]byte, error) {
+	return types.GetSecretHashLock(hashFunc, secret, timestamp)
+}
+
+// isSupportedHashFunc returns whether the given hash function is enabled by the current params
+func (k Keeper) isSupportedHashFunc(ctx sdk.Context, hashFunc types.HashFunc) bool {
+	for _, hf := range k.GetParams(ctx).SupportedHashFuncs {
+		if hf == hashFunc {
+			return true
+		}
+	}
+	return false
 }
 
 func (k Keeper) HasSecretHashLock(ctx sdk.Context, secretHashLock []byte) bool {
@@ -161,16 +274,39 @@ func (k Keeper) HasSecretHashLock(ctx sdk.Context, secretHashLock []byte) bool {
 	return store.Has(KeyHTLC(secretHashLock))
 }
 
-// SetHTLC stores the htlc
+// SetHTLC stores the htlc and keeps its sender/receiver/state secondary indexes in sync so
+// queries never have to fall back to an O(n) scan of the whole HTLC store
 func (k Keeper) SetHTLC(ctx sdk.Context, htlc types.HTLC, secretHashLock []byte) {
+	htlc = normalizeHTLC(htlc)
 	store := ctx.KVStore(k.storeKey)
 
+	if existing, err := k.GetHTLC(ctx, secretHashLock); err == nil && existing.State != htlc.State {
+		store.Delete(KeyHTLCByState(existing.State, secretHashLock))
+	}
+
 	bz := k.cdc.MustMarshalBinaryLengthPrefixed(htlc)
 	store.Set(KeyHTLC(secretHashLock), bz)
+	store.Set(KeyHTLCBySender(htlc.Sender, secretHashLock), secretHashLock)
+	store.Set(KeyHTLCByReceiver(htlc.Receiver, secretHashLock), secretHashLock)
+	store.Set(KeyHTLCByState(htlc.State, secretHashLock), secretHashLock)
+
+	// Invalidate rather than write through: ctx.KVStore is a branch that may never be committed
+	// (CheckTx, simulation), and writing the new value straight into the cache here would let an
+	// uncommitted HTLC state leak into a later DeliverTx's GetHTLC. Evicting is always safe - the
+	// worst case is a cache miss that falls through to a committed store read below.
+	if k.htlcCache != nil {
+		k.htlcCache.Remove(string(secretHashLock))
+	}
 }
 
 // GetHTLC retrieves the htlc by the specified secret hash lock
 func (k Keeper) GetHTLC(ctx sdk.Context, secretHashLock []byte) (types.HTLC, sdk.Error) {
+	if k.htlcCache != nil {
+		if cached, ok := k.htlcCache.Get(string(secretHashLock)); ok {
+			return cached.(types.HTLC), nil
+		}
+	}
+
 	store := ctx.KVStore(k.storeKey)
 
 	bz := store.Get(KeyHTLC(secretHashLock))
@@ -180,16 +316,42 @@ func (k Keeper) GetHTLC(ctx sdk.Context, secretHashLock []byte) (types.HTLC, sdk
 
 	var htlc types.HTLC
 	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &htlc)
+	htlc = normalizeHTLC(htlc)
+
+	if k.htlcCache != nil {
+		k.htlcCache.Add(string(secretHashLock), htlc)
+	}
 
 	return htlc, nil
 }
 
-// AddHTLCToExpireQueue adds the htlc to the expiration queue
-func (k Keeper) AddHTLCToExpireQueue(ctx sdk.Context, expireHeight uint64, secretHashLock []byte) {
+// GetAllHTLCs returns every HTLC currently in the store, keyed by their secret hash lock
+func (k Keeper) GetAllHTLCs(ctx sdk.Context) []types.GenesisHTLC {
 	store := ctx.KVStore(k.storeKey)
 
-	bz := k.cdc.MustMarshalBinaryLengthPrefixed(secretHashLock)
-	store.Set(KeyHTLCExpireQueue(expireHeight, secretHashLock), bz)
+	iterator := sdk.KVStorePrefixIterator(store, PrefixHTLC)
+	defer iterator.Close()
+
+	htlcs := make([]types.GenesisHTLC, 0)
+	for ; iterator.Valid(); iterator.Next() {
+		secretHashLock := iterator.Key()[len(PrefixHTLC):]
+
+		var htlc types.HTLC
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &htlc)
+
+		htlcs = append(htlcs, types.GenesisHTLC{SecretHashLock: secretHashLock, HTLC: normalizeHTLC(htlc)})
+	}
+
+	return htlcs
+}
+
+// AddHTLCToExpireQueue adds the htlc to the expiration queue. The secret hash lock is already
+// part of the key (see KeyHTLCExpireQueue), so the value itself only needs to be non-nil to
+// make the key present in the store - marshalling the hash lock again into the value would be
+// pure waste on every single CreateHTLC call.
+func (k Keeper) AddHTLCToExpireQueue(ctx sdk.Context, expireHeight uint64, secretHashLock []byte) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(KeyHTLCExpireQueue(expireHeight, secretHashLock), []byte{})
 }
 
 // DeleteHTLCFromExpireQueue removes the htlc from the expiration queue
@@ -204,3 +366,14 @@ func (k Keeper) DeleteHTLCFromExpireQueue(ctx sdk.Context, expireHeight uint64,
 func getHTLCAddress(denom string) sdk.AccAddress {
 	return sdk.AccAddress(crypto.AddressHash([]byte(denom)))
 }
+
+// normalizeHTLC guarantees RelayerFee is never left as the Go zero value. A client that omits
+// the field, or an HTLC stored before the field existed, unmarshals with RelayerFee.Amount
+// wrapping a nil big.Int, which panics on IsZero/Sub/GTE - so every path that creates, stores or
+// loads a HTLC routes through here first.
+func normalizeHTLC(htlc types.HTLC) types.HTLC {
+	if htlc.RelayerFee.Amount.IsNil() {
+		htlc.RelayerFee = sdk.NewCoin(htlc.OutAmount.Denom, sdk.ZeroInt())
+	}
+	return htlc
+}
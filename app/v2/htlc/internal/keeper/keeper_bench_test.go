@@ -0,0 +1,174 @@
+package keeper
+
+import (
+	"fmt"
+	"testing"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/irisnet/irishub/app/v1/params"
+	"github.com/irisnet/irishub/app/v2/htlc/internal/types"
+	"github.com/irisnet/irishub/codec"
+	"github.com/irisnet/irishub/store"
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// noopBankKeeper satisfies types.BankKeeper without touching any account balances, so the
+// benchmarks below measure the HTLC store paths in isolation
+type noopBankKeeper struct{}
+
+func (noopBankKeeper) SendCoins(ctx sdk.Context, fromAddr, toAddr sdk.AccAddress, amt sdk.Coins) (sdk.Tags, sdk.Error) {
+	return sdk.EmptyTags(), nil
+}
+
+func benchKeeper(tb testing.TB, withCache bool) (sdk.Context, Keeper) {
+	storeKey := sdk.NewKVStoreKey(types.StoreKey)
+	paramsKey := sdk.NewKVStoreKey("params")
+	tParamsKey := sdk.NewTransientStoreKey("transient_params")
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(storeKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(paramsKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(tParamsKey, sdk.StoreTypeTransient, db)
+	if err := ms.LoadLatestVersion(); err != nil {
+		tb.Fatal(err)
+	}
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+
+	cdc := codec.New()
+	types.RegisterCodec(cdc)
+
+	paramsKeeper := params.NewKeeper(cdc, paramsKey, tParamsKey)
+	k := NewKeeper(cdc, storeKey, noopBankKeeper{}, types.DefaultCodespace, paramsKeeper.Subspace(types.DefaultParamSpace))
+	if withCache {
+		k = k.WithCache(1_000_000)
+	}
+	k.SetParams(ctx, types.DefaultParams())
+
+	return ctx, k
+}
+
+// seededHTLC bundles a benchmark fixture's secret alongside the hash lock it was stored
+// under, since ClaimHTLC verifies the secret against the hash lock on every call
+type seededHTLC struct {
+	secret         []byte
+	secretHashLock []byte
+}
+
+func seedHTLCs(b *testing.B, ctx sdk.Context, k Keeper, n int) []seededHTLC {
+	seeded := make([]seededHTLC, n)
+	sender := sdk.AccAddress([]byte("bench-sender--------"))
+	receiver := sdk.AccAddress([]byte("bench-receiver------"))
+
+	for i := 0; i < n; i++ {
+		secret := []byte(fmt.Sprintf("secret-%d", i))
+		secretHashLock, err := types.GetSecretHashLock(types.HashFuncSHA256, secret, 1)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		htlc := types.NewHTLC(sender, receiver, nil, sdk.NewCoin("iris-atto", sdk.NewInt(100)), nil, 1, uint64(i+1), types.HashFuncSHA256, sdk.NewCoin("iris-atto", sdk.ZeroInt()))
+		htlc.State = types.StateOpen
+		k.SetHTLC(ctx, htlc, secretHashLock)
+		k.AddHTLCToExpireQueue(ctx, htlc.ExpireHeight, secretHashLock)
+		seeded[i] = seededHTLC{secret: secret, secretHashLock: secretHashLock}
+	}
+
+	return seeded
+}
+
+// BenchmarkCreateHTLC measures CreateHTLC's cost with a large number of already-open HTLCs in
+// the store, representative of a busy cross-chain swap node
+func BenchmarkCreateHTLC(b *testing.B) {
+	for _, n := range []int{10_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("open=%d", n), func(b *testing.B) {
+			ctx, k := benchKeeper(b, false)
+			seedHTLCs(b, ctx, k, n)
+			sender := sdk.AccAddress([]byte("bench-sender--------"))
+			receiver := sdk.AccAddress([]byte("bench-receiver------"))
+
+			expireHeight := uint64(ctx.BlockHeight()) + types.DefaultMinTimeLock + 1
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				secretHashLock := []byte(fmt.Sprintf("new-hashlock-%d-----------------", i))[:32]
+				htlc := types.NewHTLC(sender, receiver, nil, sdk.NewCoin("iris-atto", sdk.NewInt(100)), nil, 1, expireHeight, types.HashFuncSHA256, sdk.NewCoin("iris-atto", sdk.ZeroInt()))
+				if _, err := k.CreateHTLC(ctx, htlc, secretHashLock); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkClaimHTLC measures GetHTLC + ClaimHTLC's cost, with and without the read-through
+// cache, across the same open-HTLC set sizes
+func BenchmarkClaimHTLC(b *testing.B) {
+	for _, n := range []int{10_000, 100_000, 1_000_000} {
+		for _, withCache := range []bool{false, true} {
+			b.Run(fmt.Sprintf("open=%d/cache=%t", n, withCache), func(b *testing.B) {
+				ctx, k := benchKeeper(b, withCache)
+				seeded := seedHTLCs(b, ctx, k, n)
+				claimant := sdk.AccAddress([]byte("bench-claimant------"))
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					s := seeded[i%len(seeded)]
+					if _, err := k.ClaimHTLC(ctx, claimant, s.secret, s.secretHashLock); err != nil {
+						b.Fatal(err)
+					}
+					// reopen so the next iteration can claim it again
+					htlc, _ := k.GetHTLC(ctx, s.secretHashLock)
+					htlc.State = types.StateOpen
+					k.SetHTLC(ctx, htlc, s.secretHashLock)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkRefundHTLC measures RefundHTLC's cost across the same open-HTLC set sizes
+func BenchmarkRefundHTLC(b *testing.B) {
+	for _, n := range []int{10_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("open=%d", n), func(b *testing.B) {
+			ctx, k := benchKeeper(b, false)
+			seeded := seedHTLCs(b, ctx, k, n)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				secretHashLock := seeded[i%len(seeded)].secretHashLock
+				htlc, _ := k.GetHTLC(ctx, secretHashLock)
+				htlc.State = types.StateExpired
+				k.SetHTLC(ctx, htlc, secretHashLock)
+
+				if _, err := k.RefundHTLC(ctx, secretHashLock); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkExpireQueueIteration measures how long a full EndBlocker expiration pass takes to
+// walk the expire queue across the same open-HTLC set sizes
+func BenchmarkExpireQueueIteration(b *testing.B) {
+	for _, n := range []int{10_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("open=%d", n), func(b *testing.B) {
+			ctx, k := benchKeeper(b, false)
+			seedHTLCs(b, ctx, k, n)
+			ctx = ctx.WithBlockHeight(int64(n) + 1)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// run each pass on a fresh branch of the seeded store, so every iteration walks
+				// all n queue entries instead of the queue the previous pass already emptied
+				cacheCtx, _ := ctx.CacheContext()
+				k.expireHTLCs(cacheCtx)
+			}
+		})
+	}
+}
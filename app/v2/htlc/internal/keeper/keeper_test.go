@@ -0,0 +1,304 @@
+package keeper
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/irisnet/irishub/app/v2/htlc/internal/types"
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// findEventAttr looks up the value of attrKey on the first emitted event of type eventType, so
+// tests can assert on the typed sdk.Event layer rather than the legacy sdk.Tags shim.
+func findEventAttr(events sdk.Events, eventType, attrKey string) (string, bool) {
+	for _, event := range events {
+		if event.Type != eventType {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if string(attr.Key) == attrKey {
+				return string(attr.Value), true
+			}
+		}
+	}
+	return "", false
+}
+
+// htlcTestKeeper is routeTestKeeper under a name that reads naturally from a plain-HTLC test -
+// both need the same fixture: a fresh store-backed keeper with default params and a bank
+// keeper that records who actually got paid.
+func htlcTestKeeper(t *testing.T) (sdk.Context, Keeper, *trackingBankKeeper) {
+	return routeTestKeeper(t)
+}
+
+func makeHTLC(sender, receiver sdk.AccAddress, denom string, secret []byte, expireHeight uint64) (types.HTLC, []byte) {
+	secretHashLock, _ := types.GetSecretHashLock(types.HashFuncSHA256, secret, 1)
+	htlc := types.NewHTLC(sender, receiver, nil, sdk.NewCoin(denom, sdk.NewInt(100)), nil, 1, expireHeight, types.HashFuncSHA256, sdk.Coin{})
+	return htlc, secretHashLock
+}
+
+// TestClaimHTLC_HappyPath verifies a receiver can claim an open HTLC with the right secret and
+// is paid the full locked amount when no relayer fee is involved.
+func TestClaimHTLC_HappyPath(t *testing.T) {
+	ctx, k, bk := htlcTestKeeper(t)
+	sender := sdk.AccAddress([]byte("sender--------------"))
+	receiver := sdk.AccAddress([]byte("receiver------------"))
+	secret := []byte("correct-secret")
+	htlc, secretHashLock := makeHTLC(sender, receiver, "iris-atto", secret, 300)
+
+	if _, err := k.CreateHTLC(ctx, htlc, secretHashLock); err != nil {
+		t.Fatalf("CreateHTLC failed: %v", err)
+	}
+
+	if _, err := k.ClaimHTLC(ctx, receiver, secret, secretHashLock); err != nil {
+		t.Fatalf("ClaimHTLC failed: %v", err)
+	}
+
+	if got := bk.balances[receiver.String()].AmountOf("iris-atto").Int64(); got != 100 {
+		t.Fatalf("expected receiver to be paid 100, got %d", got)
+	}
+
+	stored, err := k.GetHTLC(ctx, secretHashLock)
+	if err != nil {
+		t.Fatalf("GetHTLC failed: %v", err)
+	}
+	if stored.State != types.StateCompleted {
+		t.Fatalf("expected state Completed, got %s", stored.State)
+	}
+}
+
+// TestRefundHTLC_HappyPath verifies an expired HTLC refunds its full locked amount back to
+// the sender.
+func TestRefundHTLC_HappyPath(t *testing.T) {
+	ctx, k, bk := htlcTestKeeper(t)
+	sender := sdk.AccAddress([]byte("sender--------------"))
+	receiver := sdk.AccAddress([]byte("receiver------------"))
+	secret := []byte("correct-secret")
+	htlc, secretHashLock := makeHTLC(sender, receiver, "iris-atto", secret, 300)
+
+	if _, err := k.CreateHTLC(ctx, htlc, secretHashLock); err != nil {
+		t.Fatalf("CreateHTLC failed: %v", err)
+	}
+
+	stored, err := k.GetHTLC(ctx, secretHashLock)
+	if err != nil {
+		t.Fatalf("GetHTLC failed: %v", err)
+	}
+	stored.State = types.StateExpired
+	k.SetHTLC(ctx, stored, secretHashLock)
+
+	if _, err := k.RefundHTLC(ctx, secretHashLock); err != nil {
+		t.Fatalf("RefundHTLC failed: %v", err)
+	}
+
+	if got := bk.balances[sender.String()].AmountOf("iris-atto").Int64(); got != 100 {
+		t.Fatalf("expected sender to be refunded 100, got %d", got)
+	}
+}
+
+// TestClaimHTLC_WrongSecret verifies an incorrect secret is rejected rather than silently
+// treated as a claim, which would let anyone guess their way into someone else's HTLC.
+func TestClaimHTLC_WrongSecret(t *testing.T) {
+	ctx, k, _ := htlcTestKeeper(t)
+	sender := sdk.AccAddress([]byte("sender--------------"))
+	receiver := sdk.AccAddress([]byte("receiver------------"))
+	htlc, secretHashLock := makeHTLC(sender, receiver, "iris-atto", []byte("correct-secret"), 300)
+
+	if _, err := k.CreateHTLC(ctx, htlc, secretHashLock); err != nil {
+		t.Fatalf("CreateHTLC failed: %v", err)
+	}
+
+	if _, err := k.ClaimHTLC(ctx, receiver, []byte("wrong-secret"), secretHashLock); err == nil {
+		t.Fatal("expected ClaimHTLC to reject an incorrect secret")
+	}
+}
+
+// TestCreateHTLC_RejectsUnsupportedHashFunc verifies CreateHTLC checks the HTLC's hash
+// function against the network-governed SupportedHashFuncs allowlist rather than trusting
+// whatever the creator specifies.
+func TestCreateHTLC_RejectsUnsupportedHashFunc(t *testing.T) {
+	ctx, k, _ := htlcTestKeeper(t)
+
+	params := types.DefaultParams()
+	params.SupportedHashFuncs = []types.HashFunc{types.HashFuncSHA256}
+	k.SetParams(ctx, params)
+
+	sender := sdk.AccAddress([]byte("sender--------------"))
+	receiver := sdk.AccAddress([]byte("receiver------------"))
+	secret := []byte("correct-secret")
+	secretHashLock, _ := types.GetSecretHashLock(types.HashFuncKECCAK256, secret, 1)
+	htlc := types.NewHTLC(sender, receiver, nil, sdk.NewCoin("iris-atto", sdk.NewInt(100)), nil, 1, 300, types.HashFuncKECCAK256, sdk.Coin{})
+
+	if _, err := k.CreateHTLC(ctx, htlc, secretHashLock); err == nil {
+		t.Fatal("expected CreateHTLC to reject a hash function not in SupportedHashFuncs")
+	}
+}
+
+// TestClaimHTLC_RejectsMismatchedHashFunc verifies ClaimHTLC recomputes the hash lock using
+// the HTLC's own stored HashFunc, so a secretHashLock derived under one hash function can
+// never be satisfied by a secret meant to be checked under another.
+func TestClaimHTLC_RejectsMismatchedHashFunc(t *testing.T) {
+	ctx, k, _ := htlcTestKeeper(t)
+	sender := sdk.AccAddress([]byte("sender--------------"))
+	receiver := sdk.AccAddress([]byte("receiver------------"))
+	secret := []byte("correct-secret")
+
+	// the hash lock is derived with SHA256RAW, but the HTLC claims to use KECCAK256 - this
+	// models stored/relayed data whose HashFunc field doesn't match how its lock was computed
+	secretHashLock, _ := types.GetSecretHashLock(types.HashFuncSHA256RAW, secret, 1)
+	htlc := types.NewHTLC(sender, receiver, nil, sdk.NewCoin("iris-atto", sdk.NewInt(100)), nil, 1, 300, types.HashFuncKECCAK256, sdk.Coin{})
+
+	if _, err := k.CreateHTLC(ctx, htlc, secretHashLock); err != nil {
+		t.Fatalf("CreateHTLC failed: %v", err)
+	}
+
+	if _, err := k.ClaimHTLC(ctx, receiver, secret, secretHashLock); err == nil {
+		t.Fatal("expected ClaimHTLC to reject a secret whose hash (under the HTLC's own HashFunc) doesn't match the stored hash lock")
+	}
+}
+
+// TestCreateHTLC_EmitsTypedEvent verifies CreateHTLC emits an EventTypeCreateHTLC event
+// alongside the legacy tags, so indexers that have migrated off sdk.Tags still see it.
+func TestCreateHTLC_EmitsTypedEvent(t *testing.T) {
+	ctx, k, _ := htlcTestKeeper(t)
+	sender := sdk.AccAddress([]byte("sender--------------"))
+	receiver := sdk.AccAddress([]byte("receiver------------"))
+	htlc, secretHashLock := makeHTLC(sender, receiver, "iris-atto", []byte("correct-secret"), 300)
+
+	if _, err := k.CreateHTLC(ctx, htlc, secretHashLock); err != nil {
+		t.Fatalf("CreateHTLC failed: %v", err)
+	}
+
+	got, ok := findEventAttr(ctx.EventManager().Events(), types.EventTypeCreateHTLC, types.AttributeKeySender)
+	if !ok {
+		t.Fatal("expected an EventTypeCreateHTLC event to be emitted")
+	}
+	if got != sender.String() {
+		t.Fatalf("expected sender attribute %s, got %s", sender, got)
+	}
+}
+
+// TestClaimHTLC_EmitsTypedEvent verifies ClaimHTLC emits an EventTypeClaimHTLC event carrying
+// the revealed secret, which is what lets an indexer reconstruct a claim without replaying the
+// legacy sdk.Tags.
+func TestClaimHTLC_EmitsTypedEvent(t *testing.T) {
+	ctx, k, _ := htlcTestKeeper(t)
+	sender := sdk.AccAddress([]byte("sender--------------"))
+	receiver := sdk.AccAddress([]byte("receiver------------"))
+	secret := []byte("correct-secret")
+	htlc, secretHashLock := makeHTLC(sender, receiver, "iris-atto", secret, 300)
+
+	if _, err := k.CreateHTLC(ctx, htlc, secretHashLock); err != nil {
+		t.Fatalf("CreateHTLC failed: %v", err)
+	}
+	if _, err := k.ClaimHTLC(ctx, receiver, secret, secretHashLock); err != nil {
+		t.Fatalf("ClaimHTLC failed: %v", err)
+	}
+
+	got, ok := findEventAttr(ctx.EventManager().Events(), types.EventTypeClaimHTLC, types.AttributeKeySecret)
+	if !ok {
+		t.Fatal("expected an EventTypeClaimHTLC event to be emitted")
+	}
+	if got != hex.EncodeToString(secret) {
+		t.Fatalf("expected secret attribute %s, got %s", hex.EncodeToString(secret), got)
+	}
+}
+
+// TestRefundHTLC_EmitsTypedEvent verifies RefundHTLC emits an EventTypeRefundHTLC event once
+// an expired HTLC's funds have been returned to the sender.
+func TestRefundHTLC_EmitsTypedEvent(t *testing.T) {
+	ctx, k, _ := htlcTestKeeper(t)
+	sender := sdk.AccAddress([]byte("sender--------------"))
+	receiver := sdk.AccAddress([]byte("receiver------------"))
+	htlc, secretHashLock := makeHTLC(sender, receiver, "iris-atto", []byte("correct-secret"), 300)
+
+	if _, err := k.CreateHTLC(ctx, htlc, secretHashLock); err != nil {
+		t.Fatalf("CreateHTLC failed: %v", err)
+	}
+
+	stored, err := k.GetHTLC(ctx, secretHashLock)
+	if err != nil {
+		t.Fatalf("GetHTLC failed: %v", err)
+	}
+	stored.State = types.StateExpired
+	k.SetHTLC(ctx, stored, secretHashLock)
+
+	if _, err := k.RefundHTLC(ctx, secretHashLock); err != nil {
+		t.Fatalf("RefundHTLC failed: %v", err)
+	}
+
+	if _, ok := findEventAttr(ctx.EventManager().Events(), types.EventTypeRefundHTLC, types.AttributeKeySender); !ok {
+		t.Fatal("expected an EventTypeRefundHTLC event to be emitted")
+	}
+}
+
+// TestClaimHTLC_SplitsRelayerFeeToClaimant verifies that when a HTLC carries a RelayerFee, the
+// claimant keeps it and only the remainder reaches the receiver - the mechanism that lets a
+// watchtower/relayer claim profitably on behalf of a receiver who is offline.
+func TestClaimHTLC_SplitsRelayerFeeToClaimant(t *testing.T) {
+	ctx, k, bk := htlcTestKeeper(t)
+	sender := sdk.AccAddress([]byte("sender--------------"))
+	receiver := sdk.AccAddress([]byte("receiver------------"))
+	claimant := sdk.AccAddress([]byte("claimant------------"))
+	secret := []byte("correct-secret")
+	secretHashLock, _ := types.GetSecretHashLock(types.HashFuncSHA256, secret, 1)
+	htlc := types.NewHTLC(
+		sender, receiver, nil, sdk.NewCoin("iris-atto", sdk.NewInt(100)), nil, 1, 300,
+		types.HashFuncSHA256, sdk.NewCoin("iris-atto", sdk.NewInt(10)),
+	)
+
+	if _, err := k.CreateHTLC(ctx, htlc, secretHashLock); err != nil {
+		t.Fatalf("CreateHTLC failed: %v", err)
+	}
+
+	if _, err := k.ClaimHTLC(ctx, claimant, secret, secretHashLock); err != nil {
+		t.Fatalf("ClaimHTLC failed: %v", err)
+	}
+
+	if got := bk.balances[receiver.String()].AmountOf("iris-atto").Int64(); got != 90 {
+		t.Fatalf("expected receiver to be paid OutAmount-RelayerFee (90), got %d", got)
+	}
+	if got := bk.balances[claimant.String()].AmountOf("iris-atto").Int64(); got != 10 {
+		t.Fatalf("expected claimant to be paid the RelayerFee (10), got %d", got)
+	}
+
+	stored, err := k.GetHTLC(ctx, secretHashLock)
+	if err != nil {
+		t.Fatalf("GetHTLC failed: %v", err)
+	}
+	if !stored.Relayer.Equals(claimant) {
+		t.Fatalf("expected Relayer to be set to the claimant, got %s", stored.Relayer)
+	}
+}
+
+// TestCreateHTLC_NilRelayerFeeDefaultsToZero verifies a HTLC created with the Go zero value
+// sdk.Coin{} for RelayerFee - e.g. a pre-upgrade client that doesn't know the field exists -
+// normalizes to a zero RelayerFee instead of leaving a nil Amount that would panic the first
+// time CreateHTLC/ClaimHTLC touches it.
+func TestCreateHTLC_NilRelayerFeeDefaultsToZero(t *testing.T) {
+	ctx, k, bk := htlcTestKeeper(t)
+	sender := sdk.AccAddress([]byte("sender--------------"))
+	receiver := sdk.AccAddress([]byte("receiver------------"))
+	secret := []byte("correct-secret")
+	secretHashLock, _ := types.GetSecretHashLock(types.HashFuncSHA256, secret, 1)
+	htlc := types.NewHTLC(sender, receiver, nil, sdk.NewCoin("iris-atto", sdk.NewInt(100)), nil, 1, 300, types.HashFuncSHA256, sdk.Coin{})
+
+	if _, err := k.CreateHTLC(ctx, htlc, secretHashLock); err != nil {
+		t.Fatalf("CreateHTLC failed: %v", err)
+	}
+
+	stored, err := k.GetHTLC(ctx, secretHashLock)
+	if err != nil {
+		t.Fatalf("GetHTLC failed: %v", err)
+	}
+	if stored.RelayerFee.Amount.IsNil() || !stored.RelayerFee.IsZero() {
+		t.Fatalf("expected RelayerFee to normalize to a non-nil zero coin, got %s", stored.RelayerFee)
+	}
+
+	if _, err := k.ClaimHTLC(ctx, receiver, secret, secretHashLock); err != nil {
+		t.Fatalf("ClaimHTLC failed: %v", err)
+	}
+	if got := bk.balances[receiver.String()].AmountOf("iris-atto").Int64(); got != 100 {
+		t.Fatalf("expected receiver to be paid the full amount with no relayer fee carved out, got %d", got)
+	}
+}
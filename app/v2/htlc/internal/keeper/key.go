@@ -0,0 +1,77 @@
+package keeper
+
+import (
+	"github.com/irisnet/irishub/app/v2/htlc/internal/types"
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// key prefixes for the htlc store
+var (
+	PrefixHTLC                 = []byte{0x00}
+	PrefixHTLCExpireQueue      = []byte{0x01}
+	PrefixHTLCRoute            = []byte{0x02}
+	PrefixHTLCRouteExpireQueue = []byte{0x03}
+
+	// secondary indexes, maintained inside SetHTLC so queries never need an O(n) scan
+	PrefixHTLCBySender   = []byte{0x10}
+	PrefixHTLCByReceiver = []byte{0x11}
+	PrefixHTLCByState    = []byte{0x12}
+
+	// PrefixHTLCRouteRevealed indexes routes whose secret has been revealed but still have a hop
+	// that is neither claimed nor refunded, so EndBlocker never has to scan every route ever
+	// created to find them
+	PrefixHTLCRouteRevealed = []byte{0x13}
+)
+
+// KeyHTLC returns the store key for the HTLC with the specified secret hash lock
+func KeyHTLC(secretHashLock []byte) []byte {
+	return append(PrefixHTLC, secretHashLock...)
+}
+
+// KeyHTLCExpireQueue returns the store key for the expiration queue entry of the specified
+// HTLC at the given expiration height
+func KeyHTLCExpireQueue(expireHeight uint64, secretHashLock []byte) []byte {
+	prefix := append(PrefixHTLCExpireQueue, sdk.Uint64ToBigEndian(expireHeight)...)
+	return append(prefix, secretHashLock...)
+}
+
+// KeyHTLCRoute returns the store key for the HTLCRoute with the specified secret hash lock
+func KeyHTLCRoute(secretHashLock []byte) []byte {
+	return append(PrefixHTLCRoute, secretHashLock...)
+}
+
+// KeyHTLCRouteExpireQueue returns the store key for the expiration queue entry of the hop at
+// hopIndex in the route identified by secretHashLock, at the given expiration height
+func KeyHTLCRouteExpireQueue(expireHeight uint64, secretHashLock []byte, hopIndex int) []byte {
+	prefix := append(PrefixHTLCRouteExpireQueue, sdk.Uint64ToBigEndian(expireHeight)...)
+	prefix = append(prefix, secretHashLock...)
+	return append(prefix, byte(hopIndex))
+}
+
+// KeyHTLCBySender returns the secondary index key used to look up a sender's HTLCs without
+// scanning the whole HTLC store
+func KeyHTLCBySender(sender sdk.AccAddress, secretHashLock []byte) []byte {
+	prefix := append(PrefixHTLCBySender, sender.Bytes()...)
+	return append(prefix, secretHashLock...)
+}
+
+// KeyHTLCByReceiver returns the secondary index key used to look up a receiver's HTLCs without
+// scanning the whole HTLC store
+func KeyHTLCByReceiver(receiver sdk.AccAddress, secretHashLock []byte) []byte {
+	prefix := append(PrefixHTLCByReceiver, receiver.Bytes()...)
+	return append(prefix, secretHashLock...)
+}
+
+// KeyHTLCByState returns the secondary index key used to look up every HTLC in a given state
+// without scanning the whole HTLC store
+func KeyHTLCByState(state types.HTLCState, secretHashLock []byte) []byte {
+	prefix := append(PrefixHTLCByState, byte(state))
+	return append(prefix, secretHashLock...)
+}
+
+// KeyHTLCRouteRevealed returns the secondary index key used to look up routes with a revealed
+// secret and a hop that is neither claimed nor refunded, without scanning every route ever
+// created
+func KeyHTLCRouteRevealed(secretHashLock []byte) []byte {
+	return append(PrefixHTLCRouteRevealed, secretHashLock...)
+}
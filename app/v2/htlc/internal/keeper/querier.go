@@ -0,0 +1,103 @@
+package keeper
+
+import (
+	"github.com/irisnet/irishub/app/v2/htlc/internal/types"
+	"github.com/irisnet/irishub/codec"
+	sdk "github.com/irisnet/irishub/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// NewQuerier creates a new htlc module querier, dispatched by the app's query router under the
+// "custom/htlc/..." path
+func NewQuerier(k Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		switch path[0] {
+		case types.QueryHTLC:
+			return queryHTLC(ctx, req, k)
+		case types.QueryHTLCsBySender:
+			return queryHTLCsBySender(ctx, req, k)
+		case types.QueryHTLCsByReceiver:
+			return queryHTLCsByReceiver(ctx, req, k)
+		case types.QueryHTLCsByState:
+			return queryHTLCsByState(ctx, req, k)
+		case types.QueryExpiringHTLCs:
+			return queryExpiringHTLCs(ctx, req, k)
+		case types.QueryParams:
+			return queryParams(ctx, k)
+		default:
+			return nil, sdk.ErrUnknownRequest("unknown htlc query endpoint")
+		}
+	}
+}
+
+func queryHTLC(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryHTLCParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ParseParamsWithError(err)
+	}
+
+	htlc, err := k.GetHTLC(ctx, params.SecretHashLock)
+	if err != nil {
+		return nil, err
+	}
+
+	return mustMarshalJSONIndent(k.cdc, htlc)
+}
+
+func queryHTLCsBySender(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryHTLCsParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ParseParamsWithError(err)
+	}
+
+	htlcs := k.GetHTLCsBySender(ctx, params.Sender)
+	return mustMarshalJSONIndent(k.cdc, paginateGenesisHTLCs(htlcs, params.Page, params.Limit))
+}
+
+func queryHTLCsByReceiver(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryHTLCsParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ParseParamsWithError(err)
+	}
+
+	htlcs := k.GetHTLCsByReceiver(ctx, params.Receiver)
+	return mustMarshalJSONIndent(k.cdc, paginateGenesisHTLCs(htlcs, params.Page, params.Limit))
+}
+
+func queryHTLCsByState(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryHTLCsParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ParseParamsWithError(err)
+	}
+
+	htlcs := k.GetHTLCsByState(ctx, params.State)
+	return mustMarshalJSONIndent(k.cdc, paginateGenesisHTLCs(htlcs, params.Page, params.Limit))
+}
+
+func queryExpiringHTLCs(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params types.QueryExpiringHTLCsParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ParseParamsWithError(err)
+	}
+
+	htlcs := k.GetExpiringHTLCs(ctx, params.MaxExpireHeight)
+	return mustMarshalJSONIndent(k.cdc, paginateGenesisHTLCs(htlcs, params.Page, params.Limit))
+}
+
+func queryParams(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
+	return mustMarshalJSONIndent(k.cdc, k.GetParams(ctx))
+}
+
+// paginateGenesisHTLCs slices an already-filtered result set down to the requested page
+func paginateGenesisHTLCs(htlcs []types.GenesisHTLC, page, limit int) []types.GenesisHTLC {
+	start, end := types.Paginate(len(htlcs), page, limit)
+	return htlcs[start:end]
+}
+
+func mustMarshalJSONIndent(cdc *codec.Codec, v interface{}) ([]byte, sdk.Error) {
+	bz, err := codec.MarshalJSONIndent(cdc, v)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
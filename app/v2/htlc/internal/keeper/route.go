@@ -0,0 +1,233 @@
+package keeper
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/irisnet/irishub/app/v2/htlc/internal/types"
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// CreateHTLCRoute locks the funds for every hop of a multi-hop route. The sender pre-funds all
+// hops up front since they are the only party with on-chain capital at route-creation time, so
+// every hop's escrow is also refunded back to the sender if it expires; a claimed hop, however,
+// is paid out straight to its own receiver independently, which is what lets the cascade in
+// EndBlocker pay relayers without a second round trip through the sender.
+func (k Keeper) CreateHTLCRoute(ctx sdk.Context, route types.HTLCRoute) (sdk.Tags, sdk.Error) {
+	if k.HasSecretHashLock(ctx, route.SecretHashLock) || k.HasHTLCRoute(ctx, route.SecretHashLock) {
+		return nil, types.ErrSecretHashLockAlreadyExists(k.codespace, fmt.Sprintf("the secret hash lock already exists: %s", hex.EncodeToString(route.SecretHashLock)))
+	}
+
+	if !k.isSupportedHashFunc(ctx, route.HashFunc) {
+		return nil, types.ErrInvalidHashFunc(k.codespace, fmt.Sprintf("hash function is not supported: %s", route.HashFunc))
+	}
+
+	if err := types.ValidateHops(route.Hops); err != nil {
+		return nil, types.ErrInvalidHopOrder(k.codespace, err.Error())
+	}
+
+	// every hop is subject to the same timelock bounds as a plain HTLC, so a route can't be used
+	// to bypass CreateHTLC's griefing protection with a dead-on-arrival or absurdly long hop
+	params := k.GetParams(ctx)
+	for _, hop := range route.Hops {
+		timeLock := hop.ExpireHeight - uint64(ctx.BlockHeight())
+		if hop.ExpireHeight <= uint64(ctx.BlockHeight()) || timeLock < params.MinTimeLock || timeLock > params.MaxTimeLock {
+			return nil, types.ErrInvalidTimeLock(k.codespace, fmt.Sprintf("hop time lock must be between %d and %d blocks", params.MinTimeLock, params.MaxTimeLock))
+		}
+	}
+
+	for i, hop := range route.Hops {
+		hopAddr := getHopHTLCAddress(route.SecretHashLock, i)
+		if _, err := k.bk.SendCoins(ctx, route.Sender, hopAddr, sdk.Coins{hop.Amount}); err != nil {
+			return nil, err
+		}
+		k.AddRouteHopToExpireQueue(ctx, hop.ExpireHeight, route.SecretHashLock, i)
+	}
+
+	k.SetHTLCRoute(ctx, route)
+
+	createTags := sdk.NewTags(
+		types.TagSender, []byte(route.Sender.String()),
+		types.TagSecretHashLock, []byte(hex.EncodeToString(route.SecretHashLock)),
+		types.TagReceiver, []byte(route.FinalHop().Receiver.String()),
+	)
+
+	return createTags, nil
+}
+
+// ClaimRoute claims the final hop of a route by revealing the secret. Upstream hops are not
+// paid out here: they are claimed one at a time in EndBlocker, now that the route's Secret
+// field makes the preimage available on-chain for anyone to act on.
+func (k Keeper) ClaimRoute(ctx sdk.Context, secret []byte, secretHashLock []byte) (sdk.Tags, sdk.Error) {
+	route, err := k.GetHTLCByRoute(ctx, secretHashLock)
+	if err != nil {
+		return nil, err
+	}
+
+	finalIndex := len(route.Hops) - 1
+	finalHop := route.Hops[finalIndex]
+	if finalHop.State != types.StateOpen {
+		return nil, types.ErrHopNotClaimable(k.codespace, "final hop is not open")
+	}
+
+	computedHashLock, hashErr := types.GetSecretHashLock(route.HashFunc, secret, route.Timestamp)
+	if hashErr != nil {
+		return nil, types.ErrInvalidHashFunc(k.codespace, hashErr.Error())
+	}
+	if !bytes.Equal(computedHashLock, secretHashLock) {
+		return nil, types.ErrInvalidSecret(k.codespace, fmt.Sprintf("invalid secret: %s", hex.EncodeToString(secret)))
+	}
+
+	if err := k.payHop(ctx, route.SecretHashLock, finalIndex, finalHop); err != nil {
+		return nil, err
+	}
+
+	route.Secret = secret
+	route.Hops[finalIndex].State = types.StateCompleted
+	k.SetHTLCRoute(ctx, route)
+	k.DeleteRouteHopFromExpireQueue(ctx, finalHop.ExpireHeight, secretHashLock, finalIndex)
+
+	// index the route so EndBlocker can find and pay out its remaining upstream hops without
+	// scanning every route ever created
+	if !routeFullySettled(route) {
+		k.AddRevealedRoute(ctx, secretHashLock)
+	}
+
+	claimTags := sdk.NewTags(
+		types.TagReceiver, []byte(finalHop.Receiver.String()),
+		types.TagSecretHashLock, []byte(hex.EncodeToString(secretHashLock)),
+		types.TagSecret, []byte(hex.EncodeToString(secret)),
+	)
+
+	return claimTags, nil
+}
+
+// RefundHTLCRoute refunds a single expired hop of a route back to route.Sender, who is the one
+// who funded every hop's escrow up front in CreateHTLCRoute. It only succeeds once the
+// downstream sibling hop has already expired or been refunded - a hop can never be
+// refunded while it might still need to pay out downstream, which is the invariant that makes
+// the reverse expiration cascade safe.
+func (k Keeper) RefundHTLCRoute(ctx sdk.Context, secretHashLock []byte, hopIndex int) (sdk.Tags, sdk.Error) {
+	route, err := k.GetHTLCByRoute(ctx, secretHashLock)
+	if err != nil {
+		return nil, err
+	}
+
+	if hopIndex < 0 || hopIndex >= len(route.Hops) {
+		return nil, types.ErrHopNotClaimable(k.codespace, fmt.Sprintf("hop index out of range: %d", hopIndex))
+	}
+
+	hop := route.Hops[hopIndex]
+	if hop.State != types.StateExpired {
+		return nil, types.ErrHopNotRefundable(k.codespace, "hop is not expired")
+	}
+
+	if hopIndex < len(route.Hops)-1 {
+		downstream := route.Hops[hopIndex+1]
+		if downstream.State != types.StateExpired && downstream.State != types.StateRefunded {
+			return nil, types.ErrHopNotRefundable(k.codespace, "downstream hop has not expired or been refunded yet")
+		}
+	}
+
+	hopAddr := getHopHTLCAddress(secretHashLock, hopIndex)
+	if _, sdkErr := k.bk.SendCoins(ctx, hopAddr, route.Sender, sdk.Coins{hop.Amount}); sdkErr != nil {
+		return nil, sdkErr
+	}
+
+	route.Hops[hopIndex].State = types.StateRefunded
+	k.SetHTLCRoute(ctx, route)
+	if routeFullySettled(route) {
+		k.DeleteRevealedRoute(ctx, secretHashLock)
+	}
+
+	refundTags := sdk.NewTags(
+		types.TagSender, []byte(route.Sender.String()),
+		types.TagSecretHashLock, []byte(hex.EncodeToString(secretHashLock)),
+		types.TagHopIndex, []byte(fmt.Sprintf("%d", hopIndex)),
+	)
+
+	return refundTags, nil
+}
+
+// payHop releases a hop's escrowed amount to its receiver
+func (k Keeper) payHop(ctx sdk.Context, secretHashLock []byte, hopIndex int, hop types.Hop) sdk.Error {
+	hopAddr := getHopHTLCAddress(secretHashLock, hopIndex)
+	_, err := k.bk.SendCoins(ctx, hopAddr, hop.Receiver, sdk.Coins{hop.Amount})
+	return err
+}
+
+// HasHTLCRoute returns whether a route exists for the specified secret hash lock
+func (k Keeper) HasHTLCRoute(ctx sdk.Context, secretHashLock []byte) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has(KeyHTLCRoute(secretHashLock))
+}
+
+// SetHTLCRoute stores the route
+func (k Keeper) SetHTLCRoute(ctx sdk.Context, route types.HTLCRoute) {
+	store := ctx.KVStore(k.storeKey)
+
+	bz := k.cdc.MustMarshalBinaryLengthPrefixed(route)
+	store.Set(KeyHTLCRoute(route.SecretHashLock), bz)
+}
+
+// GetHTLCByRoute retrieves the HTLCRoute identified by the specified secret hash lock
+func (k Keeper) GetHTLCByRoute(ctx sdk.Context, secretHashLock []byte) (types.HTLCRoute, sdk.Error) {
+	store := ctx.KVStore(k.storeKey)
+
+	bz := store.Get(KeyHTLCRoute(secretHashLock))
+	if bz == nil {
+		return types.HTLCRoute{}, types.ErrInvalidSecretHashLock(k.codespace, fmt.Sprintf("no route for secret hash lock: %s", hex.EncodeToString(secretHashLock)))
+	}
+
+	var route types.HTLCRoute
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &route)
+
+	return route, nil
+}
+
+// AddRouteHopToExpireQueue adds a route hop to the expiration queue. As with
+// AddHTLCToExpireQueue, the key already encodes everything needed to process the entry, so the
+// value is left empty.
+func (k Keeper) AddRouteHopToExpireQueue(ctx sdk.Context, expireHeight uint64, secretHashLock []byte, hopIndex int) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(KeyHTLCRouteExpireQueue(expireHeight, secretHashLock, hopIndex), []byte{})
+}
+
+// DeleteRouteHopFromExpireQueue removes a route hop from the expiration queue
+func (k Keeper) DeleteRouteHopFromExpireQueue(ctx sdk.Context, expireHeight uint64, secretHashLock []byte, hopIndex int) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(KeyHTLCRouteExpireQueue(expireHeight, secretHashLock, hopIndex))
+}
+
+// getHopHTLCAddress returns a dedicated escrow address for the hop at hopIndex within the
+// route identified by secretHashLock
+func getHopHTLCAddress(secretHashLock []byte, hopIndex int) sdk.AccAddress {
+	return getHTLCAddress(fmt.Sprintf("%s-%d", hex.EncodeToString(secretHashLock), hopIndex))
+}
+
+// AddRevealedRoute indexes a route as having a revealed secret and at least one hop that is
+// neither claimed nor refunded, so EndBlocker's claimRevealedRouteHops can find it without
+// scanning every route
+func (k Keeper) AddRevealedRoute(ctx sdk.Context, secretHashLock []byte) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(KeyHTLCRouteRevealed(secretHashLock), []byte{})
+}
+
+// DeleteRevealedRoute removes a route from the revealed-route index once every hop has reached
+// a terminal state and there is nothing left for EndBlocker to pay out
+func (k Keeper) DeleteRevealedRoute(ctx sdk.Context, secretHashLock []byte) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(KeyHTLCRouteRevealed(secretHashLock))
+}
+
+// routeFullySettled returns whether every hop of the route has reached a terminal state
+// (claimed or refunded), meaning the route needs no further EndBlocker attention
+func routeFullySettled(route types.HTLCRoute) bool {
+	for _, hop := range route.Hops {
+		if hop.State != types.StateCompleted && hop.State != types.StateRefunded {
+			return false
+		}
+	}
+	return true
+}
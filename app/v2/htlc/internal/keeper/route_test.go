@@ -0,0 +1,122 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/irisnet/irishub/app/v2/htlc/internal/types"
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// trackingBankKeeper records every transfer so tests can assert who actually ended up with the
+// funds, unlike noopBankKeeper which just discards them.
+type trackingBankKeeper struct {
+	balances map[string]sdk.Coins
+}
+
+func newTrackingBankKeeper() *trackingBankKeeper {
+	return &trackingBankKeeper{balances: make(map[string]sdk.Coins)}
+}
+
+func (bk *trackingBankKeeper) SendCoins(ctx sdk.Context, fromAddr, toAddr sdk.AccAddress, amt sdk.Coins) (sdk.Tags, sdk.Error) {
+	bk.balances[toAddr.String()] = bk.balances[toAddr.String()].Add(amt)
+	return sdk.EmptyTags(), nil
+}
+
+func routeTestKeeper(t *testing.T) (sdk.Context, Keeper, *trackingBankKeeper) {
+	ctx, k := benchKeeper(t, false)
+	bk := newTrackingBankKeeper()
+	k.bk = bk
+	return ctx, k, bk
+}
+
+func makeRoute(sender, receiver sdk.AccAddress, denom string) types.HTLCRoute {
+	secret := []byte("route-secret")
+	secretHashLock, _ := types.GetSecretHashLock(types.HashFuncSHA256, secret, 1)
+	hops := []types.Hop{
+		{Receiver: sdk.AccAddress([]byte("relayer-------------")), Amount: sdk.NewCoin(denom, sdk.NewInt(100)), ExpireHeight: 300},
+		{Receiver: receiver, Amount: sdk.NewCoin(denom, sdk.NewInt(90)), ExpireHeight: 200},
+	}
+	return types.NewHTLCRoute(sender, secretHashLock, types.HashFuncSHA256, 1, hops)
+}
+
+// TestValidateHops_RejectsNonDecreasingExpiration makes sure hop expirations must strictly
+// decrease from sender to receiver, which is what prevents the HTLC "free option" attack.
+func TestValidateHops_RejectsNonDecreasingExpiration(t *testing.T) {
+	hops := []types.Hop{
+		{ExpireHeight: 200, Amount: sdk.NewCoin("iris-atto", sdk.NewInt(10))},
+		{ExpireHeight: 200, Amount: sdk.NewCoin("iris-atto", sdk.NewInt(10))},
+	}
+	if err := types.ValidateHops(hops); err == nil {
+		t.Fatal("expected non-decreasing hop expirations to be rejected")
+	}
+}
+
+// TestClaimRoute_CascadesToUpstreamHops verifies that claiming the final hop reveals the
+// secret and EndBlocker then auto-pays every still-open upstream hop with it.
+func TestClaimRoute_CascadesToUpstreamHops(t *testing.T) {
+	ctx, k, bk := routeTestKeeper(t)
+	sender := sdk.AccAddress([]byte("sender--------------"))
+	receiver := sdk.AccAddress([]byte("receiver------------"))
+	route := makeRoute(sender, receiver, "iris-atto")
+
+	if _, err := k.CreateHTLCRoute(ctx, route); err != nil {
+		t.Fatalf("CreateHTLCRoute failed: %v", err)
+	}
+
+	secret := []byte("route-secret")
+	if _, err := k.ClaimRoute(ctx, secret, route.SecretHashLock); err != nil {
+		t.Fatalf("ClaimRoute failed: %v", err)
+	}
+	if bk.balances[receiver.String()].AmountOf("iris-atto").Int64() != 90 {
+		t.Fatalf("expected final receiver to be paid, got %s", bk.balances[receiver.String()])
+	}
+
+	// the relayer hop is still open; EndBlocker should auto-claim it using the now-revealed secret
+	k.EndBlocker(ctx)
+
+	relayer := route.Hops[0].Receiver
+	if bk.balances[relayer.String()].AmountOf("iris-atto").Int64() != 100 {
+		t.Fatalf("expected upstream hop to be auto-claimed, got %s", bk.balances[relayer.String()])
+	}
+
+	settled, err := k.GetHTLCByRoute(ctx, route.SecretHashLock)
+	if err != nil {
+		t.Fatalf("GetHTLCByRoute failed: %v", err)
+	}
+	if !routeFullySettled(settled) {
+		t.Fatal("expected route to be fully settled")
+	}
+}
+
+// TestRefundHTLCRoute_PaysSender verifies every hop's refund goes back to route.Sender, who is
+// the one who funded every hop's escrow up front in CreateHTLCRoute - not to an upstream hop's
+// receiver, which never funded the downstream hop's escrow.
+func TestRefundHTLCRoute_PaysSender(t *testing.T) {
+	ctx, k, bk := routeTestKeeper(t)
+	sender := sdk.AccAddress([]byte("sender--------------"))
+	receiver := sdk.AccAddress([]byte("receiver------------"))
+	route := makeRoute(sender, receiver, "iris-atto")
+
+	if _, err := k.CreateHTLCRoute(ctx, route); err != nil {
+		t.Fatalf("CreateHTLCRoute failed: %v", err)
+	}
+
+	// expire both hops, downstream (index 1) first so the refund invariant is satisfied
+	route.Hops[1].State = types.StateExpired
+	route.Hops[0].State = types.StateExpired
+	k.SetHTLCRoute(ctx, route)
+
+	if _, err := k.RefundHTLCRoute(ctx, route.SecretHashLock, 1); err != nil {
+		t.Fatalf("RefundHTLCRoute(1) failed: %v", err)
+	}
+	if _, err := k.RefundHTLCRoute(ctx, route.SecretHashLock, 0); err != nil {
+		t.Fatalf("RefundHTLCRoute(0) failed: %v", err)
+	}
+
+	if got := bk.balances[sender.String()].AmountOf("iris-atto").Int64(); got != 190 {
+		t.Fatalf("expected sender to be refunded both hops (190), got %d", got)
+	}
+	if got := bk.balances[route.Hops[0].Receiver.String()].AmountOf("iris-atto").Int64(); got != 0 {
+		t.Fatalf("expected upstream hop's receiver to get nothing on refund, got %d", got)
+	}
+}
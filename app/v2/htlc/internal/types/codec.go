@@ -0,0 +1,21 @@
+package types
+
+import (
+	"github.com/irisnet/irishub/codec"
+)
+
+// ModuleCdc is the codec used by the htlc module
+var ModuleCdc = codec.New()
+
+// RegisterCodec registers the htlc module's messages and types with the provided codec
+func RegisterCodec(cdc *codec.Codec) {
+	cdc.RegisterConcrete(MsgCreateHTLC{}, "irishub/htlc/MsgCreateHTLC", nil)
+	cdc.RegisterConcrete(MsgClaimHTLC{}, "irishub/htlc/MsgClaimHTLC", nil)
+	cdc.RegisterConcrete(MsgRefundHTLC{}, "irishub/htlc/MsgRefundHTLC", nil)
+	cdc.RegisterConcrete(MsgCreateHTLCRoute{}, "irishub/htlc/MsgCreateHTLCRoute", nil)
+}
+
+func init() {
+	RegisterCodec(ModuleCdc)
+	ModuleCdc.Seal()
+}
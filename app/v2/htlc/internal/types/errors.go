@@ -0,0 +1,73 @@
+package types
+
+import (
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// DefaultCodespace is the codespace for all errors defined in the htlc module
+const DefaultCodespace sdk.CodespaceType = ModuleName
+
+// htlc module error codes
+const (
+	CodeSecretHashLockAlreadyExists sdk.CodeType = 100
+	CodeInvalidSecretHashLock       sdk.CodeType = 101
+	CodeInvalidSecret               sdk.CodeType = 102
+	CodeStateIsNotOpen              sdk.CodeType = 103
+	CodeInvalidHashFunc             sdk.CodeType = 104
+	CodeInvalidHopOrder             sdk.CodeType = 105
+	CodeHopNotClaimable             sdk.CodeType = 106
+	CodeHopNotRefundable            sdk.CodeType = 107
+	CodeInvalidTimeLock             sdk.CodeType = 108
+	CodeInvalidRelayerFee           sdk.CodeType = 109
+)
+
+// ErrSecretHashLockAlreadyExists is raised when the secret hash lock is already in use
+func ErrSecretHashLockAlreadyExists(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeSecretHashLockAlreadyExists, msg)
+}
+
+// ErrInvalidSecretHashLock is raised when no HTLC exists for the given secret hash lock
+func ErrInvalidSecretHashLock(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidSecretHashLock, msg)
+}
+
+// ErrInvalidSecret is raised when the provided secret does not match the HTLC's hash lock
+func ErrInvalidSecret(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidSecret, msg)
+}
+
+// ErrStateIsNotOpen is raised when an operation requires the HTLC to be open but it is not
+func ErrStateIsNotOpen(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeStateIsNotOpen, msg)
+}
+
+// ErrInvalidHashFunc is raised when an unsupported hash function is specified
+func ErrInvalidHashFunc(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidHashFunc, msg)
+}
+
+// ErrInvalidHopOrder is raised when a route's hops are not in strictly decreasing expiration order
+func ErrInvalidHopOrder(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidHopOrder, msg)
+}
+
+// ErrHopNotClaimable is raised when a hop cannot currently be claimed
+func ErrHopNotClaimable(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeHopNotClaimable, msg)
+}
+
+// ErrHopNotRefundable is raised when a hop cannot yet be refunded because its downstream
+// sibling has not expired or been refunded
+func ErrHopNotRefundable(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeHopNotRefundable, msg)
+}
+
+// ErrInvalidTimeLock is raised when a HTLC's timelock falls outside the bounds allowed by params
+func ErrInvalidTimeLock(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidTimeLock, msg)
+}
+
+// ErrInvalidRelayerFee is raised when a HTLC's relayer fee is invalid or below the network minimum
+func ErrInvalidRelayerFee(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidRelayerFee, msg)
+}
@@ -0,0 +1,23 @@
+package types
+
+// htlc module event types and attribute keys. These are emitted through ctx.EventManager()
+// instead of the legacy sdk.Tags so indexers and relayers can subscribe to them by type over
+// the Tendermint WebSocket without parsing hex-encoded tag strings. The legacy tags are still
+// written alongside these for the deprecation window; see the Tag* variables in tags.go.
+const (
+	EventTypeCreateHTLC = "create_htlc"
+	EventTypeClaimHTLC  = "claim_htlc"
+	EventTypeRefundHTLC = "refund_htlc"
+
+	AttributeKeySender               = "sender"
+	AttributeKeyReceiver             = "receiver"
+	AttributeKeyReceiverOnOtherChain = "receiver_on_other_chain"
+	AttributeKeyHashLock             = "hashlock"
+	AttributeKeySecret               = "secret"
+	AttributeKeyExpireHeight         = "expire_height"
+	AttributeKeyDenom                = "denom"
+	AttributeKeyAmount               = "amount"
+	AttributeKeyHopIndex             = "hop_index"
+
+	AttributeValueCategory = ModuleName
+)
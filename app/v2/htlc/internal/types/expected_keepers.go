@@ -0,0 +1,10 @@
+package types
+
+import (
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// BankKeeper defines the expected bank keeper used to move coins in and out of the HTLC module account
+type BankKeeper interface {
+	SendCoins(ctx sdk.Context, fromAddr, toAddr sdk.AccAddress, amt sdk.Coins) (sdk.Tags, sdk.Error)
+}
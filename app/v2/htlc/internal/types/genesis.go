@@ -0,0 +1,31 @@
+package types
+
+// GenesisHTLC is the genesis representation of a single HTLC, keyed by its secret hash lock
+type GenesisHTLC struct {
+	SecretHashLock []byte `json:"secret_hash_lock"`
+	HTLC           HTLC   `json:"htlc"`
+}
+
+// GenesisState is the htlc module's genesis state
+type GenesisState struct {
+	Params Params        `json:"params"`
+	HTLCs  []GenesisHTLC `json:"htlcs"`
+}
+
+// NewGenesisState constructs a new GenesisState
+func NewGenesisState(params Params, htlcs []GenesisHTLC) GenesisState {
+	return GenesisState{
+		Params: params,
+		HTLCs:  htlcs,
+	}
+}
+
+// DefaultGenesisState returns the default genesis state for the htlc module
+func DefaultGenesisState() GenesisState {
+	return NewGenesisState(DefaultParams(), []GenesisHTLC{})
+}
+
+// ValidateGenesis validates the provided htlc genesis state
+func ValidateGenesis(data GenesisState) error {
+	return data.Params.Validate()
+}
@@ -0,0 +1,77 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/irisnet/irishub/types"
+	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/crypto/sha3"
+)
+
+// HashFunc identifies the hash function used to compute a HTLC's secret hash lock.
+// Cross-chain swap counterparties (Ethereum, Bitcoin/Lightning) do not all agree on
+// SHA-256(secret || timestamp), so the hash function travels with the HTLC itself.
+type HashFunc byte
+
+const (
+	// HashFuncSHA256 hashes SHA256(secret || timestamp), the original irishub behaviour
+	HashFuncSHA256 HashFunc = 0x00
+	// HashFuncKECCAK256 hashes Keccak256(secret || timestamp), compatible with Ethereum HTLC contracts
+	HashFuncKECCAK256 HashFunc = 0x01
+	// HashFuncRIPEMD160 hashes RIPEMD160(secret || timestamp)
+	HashFuncRIPEMD160 HashFunc = 0x02
+	// HashFuncSHA256RAW hashes SHA256(secret) with no timestamp, compatible with Bitcoin/Lightning HTLCs
+	HashFuncSHA256RAW HashFunc = 0x03
+)
+
+// String returns the string representation of a HashFunc
+func (hf HashFunc) String() string {
+	switch hf {
+	case HashFuncSHA256:
+		return "sha256"
+	case HashFuncKECCAK256:
+		return "keccak256"
+	case HashFuncRIPEMD160:
+		return "ripemd160"
+	case HashFuncSHA256RAW:
+		return "sha256-raw"
+	default:
+		return "unknown"
+	}
+}
+
+// IsValid returns whether the HashFunc is one of the supported hash functions
+func (hf HashFunc) IsValid() bool {
+	_, ok := hashFuncRegistry[hf]
+	return ok
+}
+
+// hashFuncRegistry maps each supported HashFunc to the function that derives a secret hash lock from it
+var hashFuncRegistry = map[HashFunc]func(secret []byte, timestamp uint64) []byte{
+	HashFuncSHA256: func(secret []byte, timestamp uint64) []byte {
+		return sdk.SHA256(append(secret, sdk.Uint64ToBigEndian(timestamp)...))
+	},
+	HashFuncKECCAK256: func(secret []byte, timestamp uint64) []byte {
+		hasher := sha3.NewLegacyKeccak256()
+		hasher.Write(append(secret, sdk.Uint64ToBigEndian(timestamp)...))
+		return hasher.Sum(nil)
+	},
+	HashFuncRIPEMD160: func(secret []byte, timestamp uint64) []byte {
+		hasher := ripemd160.New()
+		hasher.Write(append(secret, sdk.Uint64ToBigEndian(timestamp)...))
+		return hasher.Sum(nil)
+	},
+	HashFuncSHA256RAW: func(secret []byte, timestamp uint64) []byte {
+		// Bitcoin/Lightning-style HTLCs hash the raw secret only, with no timestamp binding
+		return sdk.SHA256(secret)
+	},
+}
+
+// GetSecretHashLock computes the secret hash lock for the given secret, timestamp and hash function
+func GetSecretHashLock(hf HashFunc, secret []byte, timestamp uint64) ([]byte, error) {
+	fn, ok := hashFuncRegistry[hf]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hash function: %s", hf)
+	}
+	return fn(secret, timestamp), nil
+}
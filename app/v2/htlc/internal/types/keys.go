@@ -0,0 +1,18 @@
+package types
+
+const (
+	// ModuleName is the name of the htlc module
+	ModuleName = "htlc"
+
+	// StoreKey is the string store representation
+	StoreKey = ModuleName
+
+	// RouterKey is the message route for the htlc module
+	RouterKey = ModuleName
+
+	// QuerierRoute is the querier route for the htlc module
+	QuerierRoute = ModuleName
+
+	// DefaultParamSpace is the default param space for the htlc module
+	DefaultParamSpace = ModuleName
+)
@@ -0,0 +1,201 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/irisnet/irishub/types"
+)
+
+const (
+	MsgTypeCreateHTLC      = "create_htlc"
+	MsgTypeClaimHTLC       = "claim_htlc"
+	MsgTypeRefundHTLC      = "refund_htlc"
+	MsgTypeCreateHTLCRoute = "create_htlc_route"
+)
+
+// MsgCreateHTLC locks the specified amount of coins in exchange for a secret hash lock
+type MsgCreateHTLC struct {
+	Sender               sdk.AccAddress `json:"sender"`
+	Receiver             sdk.AccAddress `json:"receiver"`
+	ReceiverOnOtherChain []byte         `json:"receiver_on_other_chain"`
+	Amount               sdk.Coins      `json:"amount"`
+	// HashFunc is the hash function used to derive SecretHashLock. It defaults to HashFuncSHA256
+	// for same-chain HTLCs, but relayers bridging a counterparty chain set it to whichever
+	// function that chain's HTLC already hashed the secret with.
+	HashFunc       HashFunc `json:"hash_func"`
+	SecretHashLock []byte   `json:"secret_hash_lock"`
+	Timestamp      uint64   `json:"timestamp"`
+	ExpireHeight   uint64   `json:"expire_height"`
+	// RelayerFee is carved out of Amount and paid to whoever submits MsgClaimHTLC, letting a
+	// watchtower/relayer service claim on behalf of an offline receiver.
+	RelayerFee sdk.Coin `json:"relayer_fee"`
+}
+
+// NewMsgCreateHTLC constructs a new MsgCreateHTLC
+func NewMsgCreateHTLC(
+	sender, receiver sdk.AccAddress,
+	receiverOnOtherChain []byte,
+	amount sdk.Coins,
+	hashFunc HashFunc,
+	secretHashLock []byte,
+	timestamp, expireHeight uint64,
+	relayerFee sdk.Coin,
+) MsgCreateHTLC {
+	return MsgCreateHTLC{
+		Sender:               sender,
+		Receiver:             receiver,
+		ReceiverOnOtherChain: receiverOnOtherChain,
+		Amount:               amount,
+		HashFunc:             hashFunc,
+		SecretHashLock:       secretHashLock,
+		Timestamp:            timestamp,
+		ExpireHeight:         expireHeight,
+		RelayerFee:           relayerFee,
+	}
+}
+
+// Route implements sdk.Msg
+func (msg MsgCreateHTLC) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgCreateHTLC) Type() string { return MsgTypeCreateHTLC }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgCreateHTLC) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrInvalidAddress("missing sender address")
+	}
+	if len(msg.Receiver) == 0 {
+		return sdk.ErrInvalidAddress("missing receiver address")
+	}
+	if !msg.Amount.IsValid() || !msg.Amount.IsAllPositive() {
+		return sdk.ErrInvalidCoins("htlc amount must be positive")
+	}
+	if len(msg.SecretHashLock) == 0 {
+		return sdk.ErrInvalidPubKey("missing secret hash lock")
+	}
+	if !msg.HashFunc.IsValid() {
+		return ErrInvalidHashFunc(DefaultCodespace, fmt.Sprintf("unsupported hash function: %s", msg.HashFunc))
+	}
+	if msg.ExpireHeight == 0 {
+		return sdk.NewError(DefaultCodespace, CodeStateIsNotOpen, "expire height must be greater than 0")
+	}
+	// RelayerFee is left at its Go zero value by clients that don't want a relayer fee at all,
+	// in which case Amount wraps a nil big.Int and must not be touched by IsZero/GTE below
+	if !msg.RelayerFee.Amount.IsNil() && !msg.RelayerFee.IsZero() {
+		out := msg.Amount.AmountOf(msg.RelayerFee.Denom)
+		if msg.RelayerFee.Amount.GTE(out) {
+			return sdk.ErrInvalidCoins("relayer fee must be less than the locked amount")
+		}
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (msg MsgCreateHTLC) GetSignBytes() []byte {
+	b, err := ModuleCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgCreateHTLC) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// MsgClaimHTLC claims the coins locked in an open HTLC by revealing the secret
+type MsgClaimHTLC struct {
+	Sender         sdk.AccAddress `json:"sender"`
+	SecretHashLock []byte         `json:"secret_hash_lock"`
+	Secret         []byte         `json:"secret"`
+}
+
+// NewMsgClaimHTLC constructs a new MsgClaimHTLC
+func NewMsgClaimHTLC(sender sdk.AccAddress, secretHashLock, secret []byte) MsgClaimHTLC {
+	return MsgClaimHTLC{
+		Sender:         sender,
+		SecretHashLock: secretHashLock,
+		Secret:         secret,
+	}
+}
+
+// Route implements sdk.Msg
+func (msg MsgClaimHTLC) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgClaimHTLC) Type() string { return MsgTypeClaimHTLC }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgClaimHTLC) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrInvalidAddress("missing sender address")
+	}
+	if len(msg.SecretHashLock) == 0 {
+		return sdk.ErrInvalidPubKey("missing secret hash lock")
+	}
+	if len(msg.Secret) == 0 {
+		return sdk.ErrInvalidPubKey("missing secret")
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (msg MsgClaimHTLC) GetSignBytes() []byte {
+	b, err := ModuleCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgClaimHTLC) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// MsgRefundHTLC refunds the coins locked in an expired HTLC back to the sender
+type MsgRefundHTLC struct {
+	Sender         sdk.AccAddress `json:"sender"`
+	SecretHashLock []byte         `json:"secret_hash_lock"`
+}
+
+// NewMsgRefundHTLC constructs a new MsgRefundHTLC
+func NewMsgRefundHTLC(sender sdk.AccAddress, secretHashLock []byte) MsgRefundHTLC {
+	return MsgRefundHTLC{
+		Sender:         sender,
+		SecretHashLock: secretHashLock,
+	}
+}
+
+// Route implements sdk.Msg
+func (msg MsgRefundHTLC) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgRefundHTLC) Type() string { return MsgTypeRefundHTLC }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgRefundHTLC) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrInvalidAddress("missing sender address")
+	}
+	if len(msg.SecretHashLock) == 0 {
+		return sdk.ErrInvalidPubKey("missing secret hash lock")
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (msg MsgRefundHTLC) GetSignBytes() []byte {
+	b, err := ModuleCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgRefundHTLC) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/irisnet/irishub/app/v1/params"
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// param keys for the htlc module
+var (
+	KeySupportedHashFuncs = []byte("supportedHashFuncs")
+	KeyMinRelayerFee      = []byte("minRelayerFee")
+	KeyMinTimeLock        = []byte("minTimeLock")
+	KeyMaxTimeLock        = []byte("maxTimeLock")
+)
+
+// default min/max number of blocks a HTLC may be locked for, bounding both ends of the
+// griefing window: too short and relayers can't react, too long and funds are dead capital
+const (
+	DefaultMinTimeLock uint64 = 50                // ~5 minutes at a 6s block time
+	DefaultMaxTimeLock uint64 = 10 * 60 * 24 * 7   // ~1 week at a 6s block time
+)
+
+// Params defines the parameters for the htlc module
+type Params struct {
+	// SupportedHashFuncs lists the hash functions that MsgCreateHTLC is allowed to use for its
+	// secret hash lock. Restricting this by governance keeps relayers from locking funds with
+	// a hash function the rest of the network cannot later verify.
+	SupportedHashFuncs []HashFunc `json:"supported_hash_funcs"`
+	// MinRelayerFee is the smallest RelayerFee a HTLC may offer. It exists so a relayer
+	// watching for claimable HTLCs can always expect it to be worth the gas to claim one on a
+	// receiver's behalf. It only constrains HTLCs whose RelayerFee is denominated the same as
+	// MinRelayerFee itself - an HTLC locking any other denom has no enforced minimum. An HTLC
+	// that offers no relayer fee at all (a zero RelayerFee) is not held to it either.
+	MinRelayerFee sdk.Coin `json:"min_relayer_fee"`
+	// MinTimeLock and MaxTimeLock bound how many blocks may elapse between a HTLC's creation
+	// and its ExpireHeight, preventing griefing via an absurdly short (unclaimable in practice)
+	// or absurdly long (funds locked forever) timelock.
+	MinTimeLock uint64 `json:"min_time_lock"`
+	MaxTimeLock uint64 `json:"max_time_lock"`
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() params.ParamSetPairs {
+	return params.ParamSetPairs{
+		{Key: KeySupportedHashFuncs, Value: &p.SupportedHashFuncs},
+		{Key: KeyMinRelayerFee, Value: &p.MinRelayerFee},
+		{Key: KeyMinTimeLock, Value: &p.MinTimeLock},
+		{Key: KeyMaxTimeLock, Value: &p.MaxTimeLock},
+	}
+}
+
+// ParamTypeTable returns the type table for the htlc module
+func ParamTypeTable() params.TypeTable {
+	return params.NewTypeTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns the default htlc module parameters
+func DefaultParams() Params {
+	return Params{
+		SupportedHashFuncs: []HashFunc{HashFuncSHA256, HashFuncKECCAK256, HashFuncRIPEMD160, HashFuncSHA256RAW},
+		MinRelayerFee:      sdk.NewCoin(sdk.IrisAtto, sdk.ZeroInt()),
+		MinTimeLock:        DefaultMinTimeLock,
+		MaxTimeLock:        DefaultMaxTimeLock,
+	}
+}
+
+// Validate validates a set of params
+func (p Params) Validate() error {
+	for _, hf := range p.SupportedHashFuncs {
+		if !hf.IsValid() {
+			return fmt.Errorf("unsupported hash function in params: %s", hf)
+		}
+	}
+	if p.MinTimeLock == 0 {
+		return fmt.Errorf("min time lock must be greater than 0")
+	}
+	if p.MaxTimeLock < p.MinTimeLock {
+		return fmt.Errorf("max time lock must not be less than min time lock")
+	}
+	return nil
+}
+
+// String implements fmt.Stringer
+func (p Params) String() string {
+	return fmt.Sprintf(
+		"SupportedHashFuncs: %v\nMinRelayerFee: %s\nMinTimeLock: %d\nMaxTimeLock: %d",
+		p.SupportedHashFuncs, p.MinRelayerFee, p.MinTimeLock, p.MaxTimeLock,
+	)
+}
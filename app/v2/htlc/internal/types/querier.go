@@ -0,0 +1,74 @@
+package types
+
+import (
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// query endpoints supported by the htlc module's querier
+const (
+	QueryHTLC            = "htlc"
+	QueryHTLCsBySender   = "htlcs-by-sender"
+	QueryHTLCsByReceiver = "htlcs-by-receiver"
+	QueryHTLCsByState    = "htlcs-by-state"
+	QueryExpiringHTLCs   = "expiring-htlcs"
+	QueryParams          = "params"
+)
+
+
+// QueryHTLCParams is the params for 'custom/htlc/htlc'
+type QueryHTLCParams struct {
+	SecretHashLock []byte `json:"secret_hash_lock"`
+}
+
+// NewQueryHTLCParams constructs a new QueryHTLCParams
+func NewQueryHTLCParams(secretHashLock []byte) QueryHTLCParams {
+	return QueryHTLCParams{SecretHashLock: secretHashLock}
+}
+
+// QueryHTLCsParams is the params for 'custom/htlc/htlcs-by-sender', 'htlcs-by-receiver' and
+// 'htlcs-by-state', paginated with Page/Limit like the rest of the module's list queries
+type QueryHTLCsParams struct {
+	Sender   sdk.AccAddress `json:"sender,omitempty"`
+	Receiver sdk.AccAddress `json:"receiver,omitempty"`
+	State    HTLCState      `json:"state,omitempty"`
+	Page     int            `json:"page"`
+	Limit    int            `json:"limit"`
+}
+
+// NewQueryHTLCsParams constructs a new QueryHTLCsParams
+func NewQueryHTLCsParams(page, limit int) QueryHTLCsParams {
+	return QueryHTLCsParams{Page: page, Limit: limit}
+}
+
+// QueryExpiringHTLCsParams is the params for 'custom/htlc/expiring-htlcs'
+type QueryExpiringHTLCsParams struct {
+	MaxExpireHeight uint64 `json:"max_expire_height"`
+	Page            int    `json:"page"`
+	Limit           int    `json:"limit"`
+}
+
+// NewQueryExpiringHTLCsParams constructs a new QueryExpiringHTLCsParams
+func NewQueryExpiringHTLCsParams(maxExpireHeight uint64, page, limit int) QueryExpiringHTLCsParams {
+	return QueryExpiringHTLCsParams{MaxExpireHeight: maxExpireHeight, Page: page, Limit: limit}
+}
+
+// Paginate returns the [start, end) bounds of the requested page within a result of total
+// items, for the caller to slice a []GenesisHTLC-shaped result with. It lives here,
+// rather than in the keeper, since every list query in this module pages the same way.
+func Paginate(total, page, limit int) (start, end int) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if page <= 0 {
+		page = 1
+	}
+	start = (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end = start + limit
+	if end > total {
+		end = total
+	}
+	return start, end
+}
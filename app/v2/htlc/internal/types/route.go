@@ -0,0 +1,136 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// Hop is a single leg of a multi-hop HTLC route. Hops are ordered from the original sender
+// (index 0) to the final receiver (the last index). Every hop's escrow is funded by the
+// route's Sender, not by the receiver of the hop before it.
+type Hop struct {
+	Receiver     sdk.AccAddress `json:"receiver"`
+	Amount       sdk.Coin       `json:"amount"`
+	ExpireHeight uint64         `json:"expire_height"`
+	State        HTLCState      `json:"state"`
+}
+
+// HTLCRoute is a chain of HTLCs that all share a single secret hash lock, allowing a payment
+// to hop through intermediate relayers (Lightning-style) while still settling atomically:
+// revealing the secret to claim the final hop lets every upstream hop be claimed with the same
+// secret, and nobody can be left holding an expired hop without its downstream sibling also
+// having expired.
+type HTLCRoute struct {
+	Sender         sdk.AccAddress `json:"sender"`
+	SecretHashLock []byte         `json:"secret_hash_lock"`
+	HashFunc       HashFunc       `json:"hash_func"`
+	Timestamp      uint64         `json:"timestamp"`
+	// Secret is empty until the final hop is claimed, at which point it is recorded here so
+	// upstream hops can be auto-claimed without the claimant having to resubmit it.
+	Secret []byte `json:"secret"`
+	Hops   []Hop  `json:"hops"`
+}
+
+// NewHTLCRoute constructs a new HTLCRoute with every hop open
+func NewHTLCRoute(sender sdk.AccAddress, secretHashLock []byte, hashFunc HashFunc, timestamp uint64, hops []Hop) HTLCRoute {
+	for i := range hops {
+		hops[i].State = StateOpen
+	}
+	return HTLCRoute{
+		Sender:         sender,
+		SecretHashLock: secretHashLock,
+		HashFunc:       hashFunc,
+		Timestamp:      timestamp,
+		Hops:           hops,
+	}
+}
+
+// FinalHop returns the last hop in the route, i.e. the one paid to the ultimate receiver
+func (r HTLCRoute) FinalHop() Hop {
+	return r.Hops[len(r.Hops)-1]
+}
+
+// ValidateHops checks that hop expirations strictly decrease from the first hop to the last.
+// This ordering is what prevents the classic HTLC "free option" attack: a relayer must always
+// observe its downstream neighbour's hop expire (or be claimed) before its own hop can expire,
+// so it never refunds upstream while still exposed to a downstream claim.
+func ValidateHops(hops []Hop) error {
+	if len(hops) == 0 {
+		return fmt.Errorf("a route must contain at least one hop")
+	}
+	for i := 0; i < len(hops)-1; i++ {
+		if hops[i].ExpireHeight <= hops[i+1].ExpireHeight {
+			return fmt.Errorf("hop %d must expire strictly after hop %d", i, i+1)
+		}
+		if hops[i].Amount.Denom != hops[i+1].Amount.Denom {
+			return fmt.Errorf("all hops in a route must use the same denom")
+		}
+	}
+	return nil
+}
+
+// MsgCreateHTLCRoute locks funds along a chain of hops that share a single secret hash lock
+type MsgCreateHTLCRoute struct {
+	Sender         sdk.AccAddress `json:"sender"`
+	Hops           []Hop          `json:"hops"`
+	HashFunc       HashFunc       `json:"hash_func"`
+	SecretHashLock []byte         `json:"secret_hash_lock"`
+	Timestamp      uint64         `json:"timestamp"`
+}
+
+// NewMsgCreateHTLCRoute constructs a new MsgCreateHTLCRoute
+func NewMsgCreateHTLCRoute(sender sdk.AccAddress, hops []Hop, hashFunc HashFunc, secretHashLock []byte, timestamp uint64) MsgCreateHTLCRoute {
+	return MsgCreateHTLCRoute{
+		Sender:         sender,
+		Hops:           hops,
+		HashFunc:       hashFunc,
+		SecretHashLock: secretHashLock,
+		Timestamp:      timestamp,
+	}
+}
+
+// Route implements sdk.Msg
+func (msg MsgCreateHTLCRoute) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (msg MsgCreateHTLCRoute) Type() string { return MsgTypeCreateHTLCRoute }
+
+// ValidateBasic implements sdk.Msg
+func (msg MsgCreateHTLCRoute) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrInvalidAddress("missing sender address")
+	}
+	if len(msg.SecretHashLock) == 0 {
+		return sdk.ErrInvalidPubKey("missing secret hash lock")
+	}
+	if !msg.HashFunc.IsValid() {
+		return ErrInvalidHashFunc(DefaultCodespace, fmt.Sprintf("unsupported hash function: %s", msg.HashFunc))
+	}
+	for _, hop := range msg.Hops {
+		if len(hop.Receiver) == 0 {
+			return sdk.ErrInvalidAddress("missing hop receiver address")
+		}
+		if !hop.Amount.IsPositive() {
+			return sdk.ErrInvalidCoins("hop amount must be positive")
+		}
+	}
+	if err := ValidateHops(msg.Hops); err != nil {
+		return ErrInvalidHopOrder(DefaultCodespace, err.Error())
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (msg MsgCreateHTLCRoute) GetSignBytes() []byte {
+	b, err := ModuleCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(b)
+}
+
+// GetSigners implements sdk.Msg
+func (msg MsgCreateHTLCRoute) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
@@ -0,0 +1,11 @@
+package types
+
+// htlc module event tags
+var (
+	TagSender               = "sender"
+	TagReceiver             = "receiver"
+	TagReceiverOnOtherChain = "receiver-on-other-chain"
+	TagSecretHashLock       = "secret-hash-lock"
+	TagSecret               = "secret"
+	TagHopIndex             = "hop-index"
+)
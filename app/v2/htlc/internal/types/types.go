@@ -0,0 +1,101 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/irisnet/irishub/types"
+)
+
+// HTLCState is the state of a HTLC
+type HTLCState byte
+
+const (
+	StateOpen      HTLCState = 0x00 // HTLC is open and can be claimed or has not yet expired
+	StateCompleted HTLCState = 0x01 // HTLC has been claimed with the correct secret
+	StateExpired   HTLCState = 0x02 // HTLC has passed its expiration height and is awaiting refund
+	StateRefunded  HTLCState = 0x03 // HTLC has been refunded to the sender
+)
+
+// String returns the string representation of a HTLCState
+func (state HTLCState) String() string {
+	switch state {
+	case StateOpen:
+		return "open"
+	case StateCompleted:
+		return "completed"
+	case StateExpired:
+		return "expired"
+	case StateRefunded:
+		return "refunded"
+	default:
+		return "unknown"
+	}
+}
+
+// HTLC represents a single hash time locked contract
+type HTLC struct {
+	Sender               sdk.AccAddress `json:"sender"`
+	Receiver             sdk.AccAddress `json:"receiver"`
+	ReceiverOnOtherChain []byte         `json:"receiver_on_other_chain"`
+	OutAmount            sdk.Coin       `json:"out_amount"`
+	Secret               []byte         `json:"secret"`
+	Timestamp            uint64         `json:"timestamp"`
+	ExpireHeight         uint64         `json:"expire_height"`
+	State                HTLCState      `json:"state"`
+	// HashFunc is the hash function the secret hash lock was derived with. It is set by the
+	// HTLC creator so the lock can match one already generated on a counterparty chain
+	// (e.g. Keccak256 for Ethereum, raw SHA256 for Bitcoin/Lightning).
+	HashFunc HashFunc `json:"hash_func"`
+	// RelayerFee is carved out of OutAmount and paid to whoever submits the claim transaction,
+	// rather than to Receiver. This lets a watchtower/relayer service claim on behalf of a
+	// receiver who is offline, which is a precondition for usable cross-chain swap UX.
+	RelayerFee sdk.Coin `json:"relayer_fee"`
+	// Relayer is set to the address that claimed the HTLC once it is claimed. It is empty
+	// while the HTLC is open.
+	Relayer sdk.AccAddress `json:"relayer"`
+}
+
+// NewHTLC constructs a new open HTLC
+func NewHTLC(
+	sender, receiver sdk.AccAddress,
+	receiverOnOtherChain []byte,
+	outAmount sdk.Coin,
+	secret []byte,
+	timestamp, expireHeight uint64,
+	hashFunc HashFunc,
+	relayerFee sdk.Coin,
+) HTLC {
+	// a nil Amount (the Go zero value, e.g. when a caller doesn't want a relayer fee) would
+	// panic the first time it's touched by IsZero/Sub/GTE, so normalize it here too
+	if relayerFee.Amount.IsNil() {
+		relayerFee = sdk.NewCoin(outAmount.Denom, sdk.ZeroInt())
+	}
+	return HTLC{
+		Sender:               sender,
+		Receiver:             receiver,
+		ReceiverOnOtherChain: receiverOnOtherChain,
+		OutAmount:            outAmount,
+		Secret:               secret,
+		Timestamp:            timestamp,
+		ExpireHeight:         expireHeight,
+		State:                StateOpen,
+		HashFunc:             hashFunc,
+		RelayerFee:           relayerFee,
+	}
+}
+
+// String implements fmt.Stringer
+func (h HTLC) String() string {
+	return fmt.Sprintf(`HTLC:
+  Sender:                  %s
+  Receiver:                %s
+  ReceiverOnOtherChain:    %x
+  OutAmount:               %s
+  Timestamp:               %d
+  ExpireHeight:            %d
+  State:                   %s
+  HashFunc:                %s
+  RelayerFee:              %s
+  Relayer:                 %s`,
+		h.Sender, h.Receiver, h.ReceiverOnOtherChain, h.OutAmount, h.Timestamp, h.ExpireHeight, h.State, h.HashFunc, h.RelayerFee, h.Relayer)
+}